@@ -0,0 +1,85 @@
+package altsrc
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/urfave/cli/v2"
+)
+
+// InitStructInputSource is used to set the default values of the flags of a
+// struct bound by cli.FlagsFromStruct from an input source, looked up by the
+// keys of their fields as given by cli.StructFields. Flags set on the command
+// line or in the environment keep their values.
+func InitStructInputSource(v interface{}, createInputSource func(context *cli.Context) (InputSourceContext, error)) cli.BeforeFunc {
+	return func(context *cli.Context) error {
+		fields, err := cli.StructFields(v)
+		if err != nil {
+			return err
+		}
+		inputSource, err := createInputSource(context)
+		if err != nil {
+			return fmt.Errorf("Unable to create input source with context: inner error: \n'%v'", err.Error())
+		}
+		return applyStructInputSource(context, inputSource, fields)
+	}
+}
+
+func applyStructInputSource(context *cli.Context, isc InputSourceContext, fields []cli.StructField) error {
+	for _, field := range fields {
+		name := field.Flag.Names()[0]
+		if context.IsSet(name) {
+			continue
+		}
+		values, err := sourceValues(isc, field.Key)
+		if err != nil {
+			return err
+		}
+		// slices and maps replace their default values when first set
+		for _, value := range values {
+			if err := context.Set(name, value); err != nil {
+				return fmt.Errorf("could not parse %q as value for flag %s: %s", value, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// sourceValues returns the named setting of the input source formatted to be
+// parsed by the flag of a struct field: a single value, the elements of a
+// list, or the entries of a map as key=value sorted by key
+func sourceValues(isc InputSourceContext, name string) ([]string, error) {
+	vs, ok := isc.(valueSource)
+	if !ok {
+		if value, err := isc.String(name); err == nil {
+			if value == "" {
+				return nil, nil
+			}
+			return []string{value}, nil
+		}
+		return isc.StringSlice(name)
+	}
+
+	value, ok := vs.value(name)
+	if !ok {
+		return nil, nil
+	}
+	switch rv := reflect.ValueOf(value); rv.Kind() {
+	case reflect.Slice:
+		values := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, formatSourceValue(rv.Index(i).Interface()))
+		}
+		return values, nil
+	case reflect.Map:
+		values := make([]string, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			values = append(values, fmt.Sprintf("%v=%s", iter.Key().Interface(), formatSourceValue(iter.Value().Interface())))
+		}
+		sort.Strings(values)
+		return values, nil
+	}
+	return []string{formatSourceValue(value)}, nil
+}
@@ -0,0 +1,79 @@
+package altsrc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+type bindServer struct {
+	Host    string
+	Timeout time.Duration
+}
+
+type bindOptions struct {
+	Name   string            `cli:"name" env:"BIND_NAME"`
+	Tags   []string          `cli:"tags"`
+	Labels map[string]string `cli:"labels"`
+	Server bindServer        `cli:"server"`
+	Port   int               `altsrc:"listen.port"`
+}
+
+func TestInitStructInputSource(t *testing.T) {
+	opts := bindOptions{Tags: []string{"default"}, Server: bindServer{Host: "localhost"}}
+	flags, err := cli.FlagsFromStruct(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got bindOptions
+	app := &cli.App{
+		Flags: flags,
+		Before: InitStructInputSource(&opts, func(*cli.Context) (InputSourceContext, error) {
+			return NewMapInputSource("config.yaml", map[interface{}]interface{}{
+				"name":   "from-config",
+				"tags":   []interface{}{"a", "b"},
+				"labels": map[interface{}]interface{}{"team": "core", "env": "prod"},
+				"server": map[interface{}]interface{}{"host": "example.com", "timeout": "5s"},
+				"listen": map[interface{}]interface{}{"port": 8080},
+			}), nil
+		}),
+		Action: func(*cli.Context) error { got = opts; return nil },
+	}
+
+	if err := app.Run([]string{"app", "--server-host", "flag.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, got, bindOptions{
+		Name:   "from-config",
+		Tags:   []string{"a", "b"},
+		Labels: map[string]string{"env": "prod", "team": "core"},
+		Server: bindServer{Host: "flag.example.com", Timeout: 5 * time.Second},
+		Port:   8080,
+	})
+}
+
+func TestInitStructInputSource_InvalidValue(t *testing.T) {
+	opts := bindOptions{}
+	flags, err := cli.FlagsFromStruct(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := &cli.App{
+		Flags: flags,
+		Before: InitStructInputSource(&opts, func(*cli.Context) (InputSourceContext, error) {
+			return NewMapInputSource("config.yaml", map[interface{}]interface{}{
+				"listen": map[interface{}]interface{}{"port": "http"},
+			}), nil
+		}),
+		Action: func(*cli.Context) error { return nil },
+	}
+
+	err = app.Run([]string{"app"})
+	if err == nil {
+		t.Fatal("expected an error for an invalid port")
+	}
+	expect(t, err.Error(), `could not parse "http" as value for flag port: parse error`)
+}
@@ -1,10 +1,13 @@
 package altsrc
 
+//go:generate go run ../internal/build/build.go generate
+
 import (
 	"fmt"
 	"path/filepath"
 	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/urfave/cli/v2"
 )
@@ -211,13 +214,20 @@ func (f *IntFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceCon
 func (f *DurationFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
 	if f.set != nil {
 		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
-			value, err := isc.Duration(f.DurationFlag.Name)
+			var value time.Duration
+			var err error
+			if eds, ok := isc.(extendedDurationSource); ok && f.Extended {
+				value, err = eds.extendedDuration(f.DurationFlag.Name)
+			} else {
+				value, err = isc.Duration(f.DurationFlag.Name)
+			}
 			if err != nil {
 				return err
 			}
 			if value > 0 {
-				for _, name := range f.Names() {
-					_ = f.set.Set(name, value.String())
+				// the names of the flag share its value, which checks its bounds
+				if err := f.set.Set(f.Names()[0], value.String()); err != nil {
+					return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
 				}
 			}
 		}
@@ -244,6 +254,57 @@ func (f *Float64Flag) ApplyInputSourceValue(context *cli.Context, isc InputSourc
 	return nil
 }
 
+// yamlTimestampLayouts are the layouts of the timestamps of YAML, which
+// gopkg.in/yaml.v2 leaves as strings in maps
+var yamlTimestampLayouts = []string{
+	"2006-1-2T15:4:5.999999999Z07:00",
+	"2006-1-2t15:4:5.999999999Z07:00",
+	"2006-1-2 15:4:5.999999999",
+	"2006-1-2",
+}
+
+// ApplyInputSourceValue applies a Timestamp value to the flagSet if required
+func (f *TimestampFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			// times such as the dates of TOML are formatted in RFC 3339
+			value, ok, err := sourceString(isc, f.TimestampFlag.Name, f.TimestampFlag)
+			if err != nil || !ok {
+				return err
+			}
+			// the names of the flag share its value
+			err = f.set.Set(f.Names()[0], value)
+			if err == nil {
+				return nil
+			}
+			// timestamps of YAML and TOML may not be in the layouts of the
+			// flag, so they are formatted in its first layout
+			location := f.Location
+			if location == nil {
+				location = time.UTC
+			}
+			for _, layout := range yamlTimestampLayouts {
+				if t, parseErr := time.ParseInLocation(layout, value, location); parseErr == nil {
+					return f.set.Set(f.Names()[0], t.In(location).Format(f.layout()))
+				}
+			}
+			return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+		}
+	}
+	return nil
+}
+
+// layout returns the first layout the flag parses timestamps in
+func (f *TimestampFlag) layout() string {
+	if f.Layout != "" {
+		return f.Layout
+	}
+	if len(f.Layouts) > 0 {
+		return f.Layouts[0]
+	}
+	return time.RFC3339
+}
+
 func isEnvVarSet(envVars []string) bool {
 	for _, envVar := range envVars {
 		if _, ok := syscall.Getenv(envVar); ok {
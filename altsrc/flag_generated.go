@@ -1,9 +1,10 @@
-// Code generated by fg; DO NOT EDIT.
+// Code generated by internal/build; DO NOT EDIT.
 
 package altsrc
 
 import (
 	"flag"
+	"fmt"
 
 	"github.com/urfave/cli/v2"
 )
@@ -27,6 +28,84 @@ func (f *BoolFlag) Apply(set *flag.FlagSet) error {
 	return f.BoolFlag.Apply(set)
 }
 
+// ByteSizeFlag is the flag type that wraps cli.ByteSizeFlag to allow
+// for other values to be specified
+type ByteSizeFlag struct {
+	*cli.ByteSizeFlag
+	set *flag.FlagSet
+}
+
+// NewByteSizeFlag creates a new ByteSizeFlag
+func NewByteSizeFlag(fl *cli.ByteSizeFlag) *ByteSizeFlag {
+	return &ByteSizeFlag{ByteSizeFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped ByteSizeFlag.Apply
+func (f *ByteSizeFlag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.ByteSizeFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies a ByteSize value to the flagSet if required
+func (f *ByteSizeFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			value, ok, err := sourceString(isc, f.ByteSizeFlag.Name, f.ByteSizeFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which a slice valued
+			// flag would append to once per name
+			if ok {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ChoiceFlag is the flag type that wraps cli.ChoiceFlag to allow
+// for other values to be specified
+type ChoiceFlag struct {
+	*cli.ChoiceFlag
+	set *flag.FlagSet
+}
+
+// NewChoiceFlag creates a new ChoiceFlag
+func NewChoiceFlag(fl *cli.ChoiceFlag) *ChoiceFlag {
+	return &ChoiceFlag{ChoiceFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped ChoiceFlag.Apply
+func (f *ChoiceFlag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.ChoiceFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies a Choice value to the flagSet if required
+func (f *ChoiceFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			value, ok, err := sourceString(isc, f.ChoiceFlag.Name, f.ChoiceFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which a slice valued
+			// flag would append to once per name
+			if ok {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // DurationFlag is the flag type that wraps cli.DurationFlag to allow
 // for other values to be specified
 type DurationFlag struct {
@@ -65,6 +144,45 @@ func (f *Float64Flag) Apply(set *flag.FlagSet) error {
 	return f.Float64Flag.Apply(set)
 }
 
+// Float64SliceFlag is the flag type that wraps cli.Float64SliceFlag to allow
+// for other values to be specified
+type Float64SliceFlag struct {
+	*cli.Float64SliceFlag
+	set *flag.FlagSet
+}
+
+// NewFloat64SliceFlag creates a new Float64SliceFlag
+func NewFloat64SliceFlag(fl *cli.Float64SliceFlag) *Float64SliceFlag {
+	return &Float64SliceFlag{Float64SliceFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped Float64SliceFlag.Apply
+func (f *Float64SliceFlag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.Float64SliceFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies Float64Slice values to the flagSet if required
+func (f *Float64SliceFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			values, err := sourceStrings(isc, f.Float64SliceFlag.Name, f.Float64SliceFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which replaces its
+			// default values when first set
+			for _, value := range values {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as slice value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // GenericFlag is the flag type that wraps cli.GenericFlag to allow
 // for other values to be specified
 type GenericFlag struct {
@@ -84,23 +202,238 @@ func (f *GenericFlag) Apply(set *flag.FlagSet) error {
 	return f.GenericFlag.Apply(set)
 }
 
-// Int64Flag is the flag type that wraps cli.Int64Flag to allow
+// HostPortFlag is the flag type that wraps cli.HostPortFlag to allow
 // for other values to be specified
-type Int64Flag struct {
-	*cli.Int64Flag
+type HostPortFlag struct {
+	*cli.HostPortFlag
 	set *flag.FlagSet
 }
 
-// NewInt64Flag creates a new Int64Flag
-func NewInt64Flag(fl *cli.Int64Flag) *Int64Flag {
-	return &Int64Flag{Int64Flag: fl, set: nil}
+// NewHostPortFlag creates a new HostPortFlag
+func NewHostPortFlag(fl *cli.HostPortFlag) *HostPortFlag {
+	return &HostPortFlag{HostPortFlag: fl, set: nil}
 }
 
 // Apply saves the flagSet for later usage calls, then calls
-// the wrapped Int64Flag.Apply
-func (f *Int64Flag) Apply(set *flag.FlagSet) error {
+// the wrapped HostPortFlag.Apply
+func (f *HostPortFlag) Apply(set *flag.FlagSet) error {
 	f.set = set
-	return f.Int64Flag.Apply(set)
+	return f.HostPortFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies a HostPort value to the flagSet if required
+func (f *HostPortFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			value, ok, err := sourceString(isc, f.HostPortFlag.Name, f.HostPortFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which a slice valued
+			// flag would append to once per name
+			if ok {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// HostPortSliceFlag is the flag type that wraps cli.HostPortSliceFlag to allow
+// for other values to be specified
+type HostPortSliceFlag struct {
+	*cli.HostPortSliceFlag
+	set *flag.FlagSet
+}
+
+// NewHostPortSliceFlag creates a new HostPortSliceFlag
+func NewHostPortSliceFlag(fl *cli.HostPortSliceFlag) *HostPortSliceFlag {
+	return &HostPortSliceFlag{HostPortSliceFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped HostPortSliceFlag.Apply
+func (f *HostPortSliceFlag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.HostPortSliceFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies HostPortSlice values to the flagSet if required
+func (f *HostPortSliceFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			values, err := sourceStrings(isc, f.HostPortSliceFlag.Name, f.HostPortSliceFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which replaces its
+			// default values when first set
+			for _, value := range values {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as slice value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// IPFlag is the flag type that wraps cli.IPFlag to allow
+// for other values to be specified
+type IPFlag struct {
+	*cli.IPFlag
+	set *flag.FlagSet
+}
+
+// NewIPFlag creates a new IPFlag
+func NewIPFlag(fl *cli.IPFlag) *IPFlag {
+	return &IPFlag{IPFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped IPFlag.Apply
+func (f *IPFlag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.IPFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies a IP value to the flagSet if required
+func (f *IPFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			value, ok, err := sourceString(isc, f.IPFlag.Name, f.IPFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which a slice valued
+			// flag would append to once per name
+			if ok {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// IPNetFlag is the flag type that wraps cli.IPNetFlag to allow
+// for other values to be specified
+type IPNetFlag struct {
+	*cli.IPNetFlag
+	set *flag.FlagSet
+}
+
+// NewIPNetFlag creates a new IPNetFlag
+func NewIPNetFlag(fl *cli.IPNetFlag) *IPNetFlag {
+	return &IPNetFlag{IPNetFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped IPNetFlag.Apply
+func (f *IPNetFlag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.IPNetFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies a IPNet value to the flagSet if required
+func (f *IPNetFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			value, ok, err := sourceString(isc, f.IPNetFlag.Name, f.IPNetFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which a slice valued
+			// flag would append to once per name
+			if ok {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// IPNetSliceFlag is the flag type that wraps cli.IPNetSliceFlag to allow
+// for other values to be specified
+type IPNetSliceFlag struct {
+	*cli.IPNetSliceFlag
+	set *flag.FlagSet
+}
+
+// NewIPNetSliceFlag creates a new IPNetSliceFlag
+func NewIPNetSliceFlag(fl *cli.IPNetSliceFlag) *IPNetSliceFlag {
+	return &IPNetSliceFlag{IPNetSliceFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped IPNetSliceFlag.Apply
+func (f *IPNetSliceFlag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.IPNetSliceFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies IPNetSlice values to the flagSet if required
+func (f *IPNetSliceFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			values, err := sourceStrings(isc, f.IPNetSliceFlag.Name, f.IPNetSliceFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which replaces its
+			// default values when first set
+			for _, value := range values {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as slice value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// IPSliceFlag is the flag type that wraps cli.IPSliceFlag to allow
+// for other values to be specified
+type IPSliceFlag struct {
+	*cli.IPSliceFlag
+	set *flag.FlagSet
+}
+
+// NewIPSliceFlag creates a new IPSliceFlag
+func NewIPSliceFlag(fl *cli.IPSliceFlag) *IPSliceFlag {
+	return &IPSliceFlag{IPSliceFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped IPSliceFlag.Apply
+func (f *IPSliceFlag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.IPSliceFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies IPSlice values to the flagSet if required
+func (f *IPSliceFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			values, err := sourceStrings(isc, f.IPSliceFlag.Name, f.IPSliceFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which replaces its
+			// default values when first set
+			for _, value := range values {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as slice value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
 }
 
 // IntFlag is the flag type that wraps cli.IntFlag to allow
@@ -122,23 +455,43 @@ func (f *IntFlag) Apply(set *flag.FlagSet) error {
 	return f.IntFlag.Apply(set)
 }
 
-// IntSliceFlag is the flag type that wraps cli.IntSliceFlag to allow
+// Int64Flag is the flag type that wraps cli.Int64Flag to allow
 // for other values to be specified
-type IntSliceFlag struct {
-	*cli.IntSliceFlag
+type Int64Flag struct {
+	*cli.Int64Flag
 	set *flag.FlagSet
 }
 
-// NewIntSliceFlag creates a new IntSliceFlag
-func NewIntSliceFlag(fl *cli.IntSliceFlag) *IntSliceFlag {
-	return &IntSliceFlag{IntSliceFlag: fl, set: nil}
+// NewInt64Flag creates a new Int64Flag
+func NewInt64Flag(fl *cli.Int64Flag) *Int64Flag {
+	return &Int64Flag{Int64Flag: fl, set: nil}
 }
 
 // Apply saves the flagSet for later usage calls, then calls
-// the wrapped IntSliceFlag.Apply
-func (f *IntSliceFlag) Apply(set *flag.FlagSet) error {
+// the wrapped Int64Flag.Apply
+func (f *Int64Flag) Apply(set *flag.FlagSet) error {
 	f.set = set
-	return f.IntSliceFlag.Apply(set)
+	return f.Int64Flag.Apply(set)
+}
+
+// ApplyInputSourceValue applies a Int64 value to the flagSet if required
+func (f *Int64Flag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			value, ok, err := sourceString(isc, f.Int64Flag.Name, f.Int64Flag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which a slice valued
+			// flag would append to once per name
+			if ok {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
 }
 
 // Int64SliceFlag is the flag type that wraps cli.Int64SliceFlag to allow
@@ -160,42 +513,43 @@ func (f *Int64SliceFlag) Apply(set *flag.FlagSet) error {
 	return f.Int64SliceFlag.Apply(set)
 }
 
-// Float64SliceFlag is the flag type that wraps cli.Float64SliceFlag to allow
-// for other values to be specified
-type Float64SliceFlag struct {
-	*cli.Float64SliceFlag
-	set *flag.FlagSet
-}
-
-// NewFloat64SliceFlag creates a new Float64SliceFlag
-func NewFloat64SliceFlag(fl *cli.Float64SliceFlag) *Float64SliceFlag {
-	return &Float64SliceFlag{Float64SliceFlag: fl, set: nil}
+// ApplyInputSourceValue applies Int64Slice values to the flagSet if required
+func (f *Int64SliceFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			values, err := sourceStrings(isc, f.Int64SliceFlag.Name, f.Int64SliceFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which replaces its
+			// default values when first set
+			for _, value := range values {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as slice value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
 }
 
-// Apply saves the flagSet for later usage calls, then calls the
-// wrapped Float64SliceFlag.Apply
-func (f *Float64SliceFlag) Apply(set *flag.FlagSet) error {
-	f.set = set
-	return f.Float64SliceFlag.Apply(set)
-}
-
-// StringFlag is the flag type that wraps cli.StringFlag to allow
+// IntSliceFlag is the flag type that wraps cli.IntSliceFlag to allow
 // for other values to be specified
-type StringFlag struct {
-	*cli.StringFlag
+type IntSliceFlag struct {
+	*cli.IntSliceFlag
 	set *flag.FlagSet
 }
 
-// NewStringFlag creates a new StringFlag
-func NewStringFlag(fl *cli.StringFlag) *StringFlag {
-	return &StringFlag{StringFlag: fl, set: nil}
+// NewIntSliceFlag creates a new IntSliceFlag
+func NewIntSliceFlag(fl *cli.IntSliceFlag) *IntSliceFlag {
+	return &IntSliceFlag{IntSliceFlag: fl, set: nil}
 }
 
 // Apply saves the flagSet for later usage calls, then calls
-// the wrapped StringFlag.Apply
-func (f *StringFlag) Apply(set *flag.FlagSet) error {
+// the wrapped IntSliceFlag.Apply
+func (f *IntSliceFlag) Apply(set *flag.FlagSet) error {
 	f.set = set
-	return f.StringFlag.Apply(set)
+	return f.IntSliceFlag.Apply(set)
 }
 
 // PathFlag is the flag type that wraps cli.PathFlag to allow
@@ -210,13 +564,32 @@ func NewPathFlag(fl *cli.PathFlag) *PathFlag {
 	return &PathFlag{PathFlag: fl, set: nil}
 }
 
-// Apply saves the flagSet for later usage calls, then calls the
-// wrapped PathFlag.Apply
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped PathFlag.Apply
 func (f *PathFlag) Apply(set *flag.FlagSet) error {
 	f.set = set
 	return f.PathFlag.Apply(set)
 }
 
+// StringFlag is the flag type that wraps cli.StringFlag to allow
+// for other values to be specified
+type StringFlag struct {
+	*cli.StringFlag
+	set *flag.FlagSet
+}
+
+// NewStringFlag creates a new StringFlag
+func NewStringFlag(fl *cli.StringFlag) *StringFlag {
+	return &StringFlag{StringFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped StringFlag.Apply
+func (f *StringFlag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.StringFlag.Apply(set)
+}
+
 // StringSliceFlag is the flag type that wraps cli.StringSliceFlag to allow
 // for other values to be specified
 type StringSliceFlag struct {
@@ -236,23 +609,101 @@ func (f *StringSliceFlag) Apply(set *flag.FlagSet) error {
 	return f.StringSliceFlag.Apply(set)
 }
 
-// Uint64Flag is the flag type that wraps cli.Uint64Flag to allow
+// TimestampFlag is the flag type that wraps cli.TimestampFlag to allow
 // for other values to be specified
-type Uint64Flag struct {
-	*cli.Uint64Flag
+type TimestampFlag struct {
+	*cli.TimestampFlag
 	set *flag.FlagSet
 }
 
-// NewUint64Flag creates a new Uint64Flag
-func NewUint64Flag(fl *cli.Uint64Flag) *Uint64Flag {
-	return &Uint64Flag{Uint64Flag: fl, set: nil}
+// NewTimestampFlag creates a new TimestampFlag
+func NewTimestampFlag(fl *cli.TimestampFlag) *TimestampFlag {
+	return &TimestampFlag{TimestampFlag: fl, set: nil}
 }
 
 // Apply saves the flagSet for later usage calls, then calls
-// the wrapped Uint64Flag.Apply
-func (f *Uint64Flag) Apply(set *flag.FlagSet) error {
+// the wrapped TimestampFlag.Apply
+func (f *TimestampFlag) Apply(set *flag.FlagSet) error {
 	f.set = set
-	return f.Uint64Flag.Apply(set)
+	return f.TimestampFlag.Apply(set)
+}
+
+// URLFlag is the flag type that wraps cli.URLFlag to allow
+// for other values to be specified
+type URLFlag struct {
+	*cli.URLFlag
+	set *flag.FlagSet
+}
+
+// NewURLFlag creates a new URLFlag
+func NewURLFlag(fl *cli.URLFlag) *URLFlag {
+	return &URLFlag{URLFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped URLFlag.Apply
+func (f *URLFlag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.URLFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies a URL value to the flagSet if required
+func (f *URLFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			value, ok, err := sourceString(isc, f.URLFlag.Name, f.URLFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which a slice valued
+			// flag would append to once per name
+			if ok {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// URLSliceFlag is the flag type that wraps cli.URLSliceFlag to allow
+// for other values to be specified
+type URLSliceFlag struct {
+	*cli.URLSliceFlag
+	set *flag.FlagSet
+}
+
+// NewURLSliceFlag creates a new URLSliceFlag
+func NewURLSliceFlag(fl *cli.URLSliceFlag) *URLSliceFlag {
+	return &URLSliceFlag{URLSliceFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped URLSliceFlag.Apply
+func (f *URLSliceFlag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.URLSliceFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies URLSlice values to the flagSet if required
+func (f *URLSliceFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			values, err := sourceStrings(isc, f.URLSliceFlag.Name, f.URLSliceFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which replaces its
+			// default values when first set
+			for _, value := range values {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as slice value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
 }
 
 // UintFlag is the flag type that wraps cli.UintFlag to allow
@@ -273,3 +724,62 @@ func (f *UintFlag) Apply(set *flag.FlagSet) error {
 	f.set = set
 	return f.UintFlag.Apply(set)
 }
+
+// ApplyInputSourceValue applies a Uint value to the flagSet if required
+func (f *UintFlag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			value, ok, err := sourceString(isc, f.UintFlag.Name, f.UintFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which a slice valued
+			// flag would append to once per name
+			if ok {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Uint64Flag is the flag type that wraps cli.Uint64Flag to allow
+// for other values to be specified
+type Uint64Flag struct {
+	*cli.Uint64Flag
+	set *flag.FlagSet
+}
+
+// NewUint64Flag creates a new Uint64Flag
+func NewUint64Flag(fl *cli.Uint64Flag) *Uint64Flag {
+	return &Uint64Flag{Uint64Flag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped Uint64Flag.Apply
+func (f *Uint64Flag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.Uint64Flag.Apply(set)
+}
+
+// ApplyInputSourceValue applies a Uint64 value to the flagSet if required
+func (f *Uint64Flag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			value, ok, err := sourceString(isc, f.Uint64Flag.Name, f.Uint64Flag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which a slice valued
+			// flag would append to once per name
+			if ok {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package altsrc
 import (
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -285,6 +286,36 @@ func TestDurationApplyInputSourceMethodEnvVarSet(t *testing.T) {
 	expect(t, 15*time.Second, c.Duration("test"))
 }
 
+func TestDurationApplyInputSourceMethodExtended(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewDurationFlag(&cli.DurationFlag{Name: "test", Extended: true}),
+		FlagName: "test",
+		MapValue: "2w",
+	})
+	expect(t, c.Duration("test"), 14*24*time.Hour)
+}
+
+func TestDurationApplyInputSourceMethodExtendedJSON(t *testing.T) {
+	isc, err := NewJSONSource([]byte(`{"test": "7d"}`))
+	expect(t, err, nil)
+	f := NewDurationFlag(&cli.DurationFlag{Name: "test", Extended: true})
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	_ = f.Apply(set)
+	c := cli.NewContext(nil, set, nil)
+	expect(t, f.ApplyInputSourceValue(c, isc), nil)
+	expect(t, c.Duration("test"), 7*24*time.Hour)
+}
+
+func TestDurationApplyInputSourceMethodBounds(t *testing.T) {
+	inputSource := NewMapInputSource("config.yaml", map[interface{}]interface{}{"test": "P30D"})
+	f := NewDurationFlag(&cli.DurationFlag{Name: "test", Extended: true, Max: 7 * 24 * time.Hour})
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	_ = f.Apply(set)
+	c := cli.NewContext(nil, set, nil)
+	err := f.ApplyInputSourceValue(c, inputSource)
+	expect(t, err.Error(), `could not parse "720h0m0s" as value for flag test: duration 30d is above the maximum of 1w`)
+}
+
 func TestFloat64ApplyInputSourceMethodSet(t *testing.T) {
 	c := runTest(t, testApplyInputSource{
 		Flag:     NewFloat64Flag(&cli.Float64Flag{Name: "test"}),
@@ -315,6 +346,281 @@ func TestFloat64ApplyInputSourceMethodEnvVarSet(t *testing.T) {
 	expect(t, 1.4, c.Float64("test"))
 }
 
+func TestInt64ApplyInputSourceMethodSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewInt64Flag(&cli.Int64Flag{Name: "test"}),
+		FlagName: "test",
+		MapValue: 1 << 40,
+	})
+	expect(t, c.Int64("test"), int64(1<<40))
+}
+
+func TestInt64ApplyInputSourceMethodContextSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:               NewInt64Flag(&cli.Int64Flag{Name: "test"}),
+		FlagName:           "test",
+		MapValue:           15,
+		ContextValueString: "7",
+	})
+	expect(t, c.Int64("test"), int64(7))
+}
+
+func TestInt64ApplyInputSourceMethodEnvVarSet(t *testing.T) {
+	var destination int64
+	_ = runTest(t, testApplyInputSource{
+		Flag:        NewInt64Flag(&cli.Int64Flag{Name: "test", EnvVars: []string{"TEST"}, Destination: &destination}),
+		FlagName:    "test",
+		MapValue:    15,
+		EnvVarName:  "TEST",
+		EnvVarValue: "10",
+	})
+	expect(t, destination, int64(10))
+}
+
+func TestUintApplyInputSourceMethodSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewUintFlag(&cli.UintFlag{Name: "test"}),
+		FlagName: "test",
+		MapValue: 15,
+	})
+	expect(t, c.Uint("test"), uint(15))
+}
+
+func TestUint64ApplyInputSourceMethodSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewUint64Flag(&cli.Uint64Flag{Name: "test"}),
+		FlagName: "test",
+		MapValue: "15",
+	})
+	expect(t, c.Uint64("test"), uint64(15))
+}
+
+func TestUintApplyInputSourceMethodInvalidValue(t *testing.T) {
+	f := NewUintFlag(&cli.UintFlag{Name: "test"})
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	_ = f.Apply(set)
+	err := f.ApplyInputSourceValue(cli.NewContext(nil, set, nil), NewMapInputSource("", map[interface{}]interface{}{"test": -1}))
+	if err == nil || !strings.HasPrefix(err.Error(), `could not parse "-1" as value for flag test`) {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+}
+
+func TestInt64SliceApplyInputSourceValue(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewInt64SliceFlag(&cli.Int64SliceFlag{Name: "test", Value: cli.NewInt64Slice(1)}),
+		FlagName: "test",
+		MapValue: []interface{}{2, 3},
+	})
+	expect(t, c.Int64Slice("test"), []int64{2, 3})
+}
+
+// typedOnlySource is an input source of another package, which returns int64
+// settings through Int64InputSource and Int64SliceInputSource only
+type typedOnlySource struct {
+	InputSourceContext
+	int64s map[string][]int64
+}
+
+func (s typedOnlySource) Int64(name string) (int64, error) {
+	return s.int64s[name][0], nil
+}
+
+func (s typedOnlySource) Int64Slice(name string) ([]int64, error) {
+	return s.int64s[name], nil
+}
+
+func TestInt64ApplyInputSourceMethodOptionalInterfaces(t *testing.T) {
+	isc := typedOnlySource{int64s: map[string][]int64{"one": {7}, "many": {2, 3}}}
+	one := NewInt64Flag(&cli.Int64Flag{Name: "one"})
+	many := NewInt64SliceFlag(&cli.Int64SliceFlag{Name: "many", Value: cli.NewInt64Slice(1)})
+
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	c := cli.NewContext(nil, set, nil)
+	expect(t, one.Apply(set), nil)
+	expect(t, many.Apply(set), nil)
+	expect(t, one.ApplyInputSourceValue(c, isc), nil)
+	expect(t, many.ApplyInputSourceValue(c, isc), nil)
+
+	expect(t, c.Int64("one"), int64(7))
+	expect(t, c.Int64Slice("many"), []int64{2, 3})
+}
+
+func TestFloat64SliceApplyInputSourceValue(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewFloat64SliceFlag(&cli.Float64SliceFlag{Name: "test"}),
+		FlagName: "test",
+		MapValue: []interface{}{1.5, 2},
+	})
+	expect(t, c.Float64Slice("test"), []float64{1.5, 2})
+}
+
+func TestFloat64SliceApplyInputSourceMethodContextSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:               NewFloat64SliceFlag(&cli.Float64SliceFlag{Name: "test"}),
+		FlagName:           "test",
+		MapValue:           []interface{}{1.5, 2},
+		ContextValueString: "3",
+	})
+	expect(t, c.Float64Slice("test"), []float64{3})
+}
+
+func TestTimestampApplyInputSourceMethodSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewTimestampFlag(&cli.TimestampFlag{Name: "test", Layout: "2006-01-02"}),
+		FlagName: "test",
+		MapValue: "2020-03-01",
+	})
+	expect(t, *c.Timestamp("test"), time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestTimestampApplyInputSourceMethodTOMLDate(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewTimestampFlag(&cli.TimestampFlag{Name: "test", Layout: "2006-01-02"}),
+		FlagName: "test",
+		MapValue: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	expect(t, *c.Timestamp("test"), time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestTimestampApplyInputSourceMethodTOMLDateTime(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewTimestampFlag(&cli.TimestampFlag{Name: "test"}),
+		FlagName: "test",
+		MapValue: time.Date(2020, 3, 1, 10, 30, 0, 500, time.UTC),
+	})
+	expect(t, *c.Timestamp("test"), time.Date(2020, 3, 1, 10, 30, 0, 500, time.UTC))
+}
+
+func TestTimestampApplyInputSourceMethodYAMLDate(t *testing.T) {
+	location := time.FixedZone("CET", 3600)
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewTimestampFlag(&cli.TimestampFlag{Name: "test", Location: location}),
+		FlagName: "test",
+		MapValue: "2020-3-1",
+	})
+	expect(t, c.Timestamp("test").Equal(time.Date(2020, 3, 1, 0, 0, 0, 0, location)), true)
+}
+
+func TestTimestampApplyInputSourceMethodInvalid(t *testing.T) {
+	inputSource := NewMapInputSource("config.yaml", map[interface{}]interface{}{"test": "March 1st"})
+	f := NewTimestampFlag(&cli.TimestampFlag{Name: "test", Layout: "2006-01-02"})
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	_ = f.Apply(set)
+	c := cli.NewContext(nil, set, nil)
+	err := f.ApplyInputSourceValue(c, inputSource)
+	expect(t, err.Error(), `could not parse "March 1st" as value for flag test: parsing time "March 1st" as "2006-01-02": cannot parse "March 1st" as "2006"`)
+}
+
+func TestChoiceApplyInputSourceMethodSet(t *testing.T) {
+	var destination string
+	_ = runTest(t, testApplyInputSource{
+		Flag: NewChoiceFlag(&cli.ChoiceFlag{
+			Name:        "test",
+			Choice:      cli.NewStringChoice("debug", "info"),
+			Value:       "info",
+			Destination: &destination,
+		}),
+		FlagName: "test",
+		MapValue: "debug",
+	})
+	expect(t, destination, "debug")
+}
+
+func TestChoiceApplyInputSourceMethodAliasedSlice(t *testing.T) {
+	var destination []string
+	_ = runTest(t, testApplyInputSource{
+		Flag: NewChoiceFlag(&cli.ChoiceFlag{
+			Name:        "test",
+			Aliases:     []string{"t", "tst"},
+			Choice:      cli.NewStringChoice("debug", "info"),
+			Destination: &destination,
+		}),
+		FlagName: "test",
+		MapValue: "debug",
+	})
+	expect(t, destination, []string{"debug"})
+}
+
+func TestByteSizeApplyInputSourceMethodSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewByteSizeFlag(&cli.ByteSizeFlag{Name: "test"}),
+		FlagName: "test",
+		MapValue: "64KiB",
+	})
+	expect(t, c.ByteSize("test"), uint64(64<<10))
+}
+
+func TestByteSizeApplyInputSourceMethodNumber(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewByteSizeFlag(&cli.ByteSizeFlag{Name: "test"}),
+		FlagName: "test",
+		MapValue: 1 << 20,
+	})
+	expect(t, c.ByteSize("test"), uint64(1<<20))
+}
+
+func TestByteSizeApplyInputSourceMethodJSONNumber(t *testing.T) {
+	isc, err := NewJSONSource([]byte(`{"test": 10000000}`))
+	expect(t, err, nil)
+	f := NewByteSizeFlag(&cli.ByteSizeFlag{Name: "test"})
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	_ = f.Apply(set)
+	c := cli.NewContext(nil, set, nil)
+	expect(t, f.ApplyInputSourceValue(c, isc), nil)
+	expect(t, c.ByteSize("test"), uint64(10000000))
+}
+
+func TestIPApplyInputSourceMethodSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewIPFlag(&cli.IPFlag{Name: "test"}),
+		FlagName: "test",
+		MapValue: "192.0.2.1",
+	})
+	expect(t, c.IP("test"), net.ParseIP("192.0.2.1"))
+}
+
+func TestIPApplyInputSourceMethodEnvVarSet(t *testing.T) {
+	var destination net.IP
+	runTest(t, testApplyInputSource{
+		Flag:        NewIPFlag(&cli.IPFlag{Name: "test", EnvVars: []string{"TEST"}, Destination: &destination}),
+		FlagName:    "test",
+		MapValue:    "192.0.2.1",
+		EnvVarName:  "TEST",
+		EnvVarValue: "::1",
+	})
+	expect(t, destination, net.ParseIP("::1"))
+}
+
+func TestIPNetSliceApplyInputSourceMethodSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewIPNetSliceFlag(&cli.IPNetSliceFlag{Name: "test"}),
+		FlagName: "test",
+		MapValue: []interface{}{"10.0.0.0/8", "192.168.0.0/16"},
+	})
+	nets := c.IPNetSlice("test")
+	expect(t, len(nets), 2)
+	expect(t, nets[1].String(), "192.168.0.0/16")
+}
+
+func TestHostPortApplyInputSourceMethodSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewHostPortFlag(&cli.HostPortFlag{Name: "test", DefaultPort: 5432}),
+		FlagName: "test",
+		MapValue: "db.example.com",
+	})
+	expect(t, c.HostPort("test"), "db.example.com:5432")
+}
+
+func TestURLApplyInputSourceMethodInvalid(t *testing.T) {
+	inputSource := NewMapInputSource("config.yaml", map[interface{}]interface{}{"test": "ftp://example.com"})
+	f := NewURLFlag(&cli.URLFlag{Name: "test", Schemes: []string{"https"}})
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	_ = f.Apply(set)
+	c := cli.NewContext(nil, set, nil)
+	err := f.ApplyInputSourceValue(c, inputSource)
+	expect(t, err.Error(), `could not parse "ftp://example.com" as value for flag test: scheme "ftp" of URL "ftp://example.com" is not one of https`)
+}
+
 func runTest(t *testing.T, test testApplyInputSource) *cli.Context {
 	inputSource := &MapInputSource{
 		file:     test.SourcePath,
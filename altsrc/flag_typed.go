@@ -0,0 +1,91 @@
+//go:build go1.18
+// +build go1.18
+
+package altsrc
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// TypedFlag is the flag type that wraps cli.TypedFlag to allow
+// for other values to be specified
+type TypedFlag[T any] struct {
+	*cli.TypedFlag[T]
+	set *flag.FlagSet
+}
+
+// NewTypedFlag creates a new TypedFlag
+func NewTypedFlag[T any](fl *cli.TypedFlag[T]) *TypedFlag[T] {
+	return &TypedFlag[T]{TypedFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped TypedFlag.Apply
+func (f *TypedFlag[T]) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.TypedFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies a value parsed by the Parser of the flag to
+// the flagSet if required
+func (f *TypedFlag[T]) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			value, ok, err := sourceString(isc, f.TypedFlag.Name, f.TypedFlag)
+			if err != nil {
+				return err
+			}
+			if ok {
+				for _, name := range f.Names() {
+					if err := f.set.Set(name, value); err != nil {
+						return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// TypedSliceFlag is the flag type that wraps cli.TypedSliceFlag to allow
+// for other values to be specified
+type TypedSliceFlag[T any] struct {
+	*cli.TypedSliceFlag[T]
+	set *flag.FlagSet
+}
+
+// NewTypedSliceFlag creates a new TypedSliceFlag
+func NewTypedSliceFlag[T any](fl *cli.TypedSliceFlag[T]) *TypedSliceFlag[T] {
+	return &TypedSliceFlag[T]{TypedSliceFlag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped TypedSliceFlag.Apply
+func (f *TypedSliceFlag[T]) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.TypedSliceFlag.Apply(set)
+}
+
+// ApplyInputSourceValue applies the values parsed by the Parser of the flag to
+// the flagSet if required
+func (f *TypedSliceFlag[T]) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			values, err := sourceStrings(isc, f.TypedSliceFlag.Name, f.TypedSliceFlag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which replaces its
+			// default values when first set
+			for _, value := range values {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as slice value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+//go:build go1.18
+// +build go1.18
+
+package altsrc
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func TestTypedApplyInputSourceValue(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:     NewTypedFlag(&cli.TypedFlag[uint]{Name: "test", Parser: cli.UintParser}),
+		FlagName: "test",
+		MapValue: 15,
+	})
+	expect(t, cli.Get[uint](c, "test"), uint(15))
+}
+
+func TestTypedApplyInputSourceMethodContextSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:               NewTypedFlag(&cli.TypedFlag[time.Duration]{Name: "test", Parser: cli.DurationParser}),
+		FlagName:           "test",
+		MapValue:           "1h",
+		ContextValueString: "5m",
+	})
+	expect(t, cli.Get[time.Duration](c, "test"), 5*time.Minute)
+}
+
+func TestTypedApplyInputSourceMethodEnvVarSet(t *testing.T) {
+	var destination uint
+	_ = runTest(t, testApplyInputSource{
+		Flag: NewTypedFlag(&cli.TypedFlag[uint]{
+			Name:        "test",
+			Parser:      cli.UintParser,
+			EnvVars:     []string{"TEST"},
+			Destination: &destination,
+		}),
+		FlagName:    "test",
+		MapValue:    15,
+		EnvVarName:  "TEST",
+		EnvVarValue: "10",
+	})
+	expect(t, destination, uint(10))
+}
+
+func TestTypedSliceApplyInputSourceValue(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag: NewTypedSliceFlag(&cli.TypedSliceFlag[time.Duration]{
+			Name:   "test",
+			Parser: cli.DurationParser,
+			Value:  []time.Duration{time.Second},
+		}),
+		FlagName: "test",
+		MapValue: []interface{}{"1m", "1h"},
+	})
+	expect(t, cli.Get[[]time.Duration](c, "test"), []time.Duration{time.Minute, time.Hour})
+}
+
+func TestTypedSliceApplyInputSourceMethodContextSet(t *testing.T) {
+	c := runTest(t, testApplyInputSource{
+		Flag:               NewTypedSliceFlag(&cli.TypedSliceFlag[uint]{Name: "test", Parser: cli.UintParser}),
+		FlagName:           "test",
+		MapValue:           []interface{}{1, 2},
+		ContextValueString: "3",
+	})
+	expect(t, cli.Get[[]uint](c, "test"), []uint{3})
+}
+
+func TestTypedApplyInputSourceValue_Mismatch(t *testing.T) {
+	slice := NewTypedSliceFlag(&cli.TypedSliceFlag[uint]{Name: "test", Parser: cli.UintParser})
+	set := flag.NewFlagSet("test", 0)
+	_ = slice.Apply(set)
+	inputSource := NewMapInputSource("", map[interface{}]interface{}{"test": 1})
+	err := slice.ApplyInputSourceValue(cli.NewContext(nil, set, nil), inputSource)
+	expect(t, err != nil, true)
+
+	scalar := NewTypedFlag(&cli.TypedFlag[uint]{Name: "test", Parser: cli.UintParser})
+	set = flag.NewFlagSet("test", 0)
+	_ = scalar.Apply(set)
+	inputSource = NewMapInputSource("", map[interface{}]interface{}{"test": "many"})
+	err = scalar.ApplyInputSourceValue(cli.NewContext(nil, set, nil), inputSource)
+	expect(t, err != nil, true)
+}
@@ -1,6 +1,8 @@
 package altsrc
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/urfave/cli/v2"
@@ -23,3 +25,158 @@ type InputSourceContext interface {
 	Generic(name string) (cli.Generic, error)
 	Bool(name string) (bool, error)
 }
+
+// Int64InputSource is implemented by input sources that return int64
+// settings. Int64Flags read other input sources through String.
+type Int64InputSource interface {
+	Int64(name string) (int64, error)
+}
+
+// UintInputSource is implemented by input sources that return uint
+// settings. UintFlags read other input sources through String.
+type UintInputSource interface {
+	Uint(name string) (uint, error)
+}
+
+// Uint64InputSource is implemented by input sources that return uint64
+// settings. Uint64Flags read other input sources through String.
+type Uint64InputSource interface {
+	Uint64(name string) (uint64, error)
+}
+
+// Int64SliceInputSource is implemented by input sources that return []int64
+// settings. Int64SliceFlags read other input sources through StringSlice.
+type Int64SliceInputSource interface {
+	Int64Slice(name string) ([]int64, error)
+}
+
+// Float64SliceInputSource is implemented by input sources that return
+// []float64 settings. Float64SliceFlags read other input sources through
+// StringSlice.
+type Float64SliceInputSource interface {
+	Float64Slice(name string) ([]float64, error)
+}
+
+// valueSource is implemented by the input sources of this package, which
+// return settings of any type for the parsers of typed flags
+type valueSource interface {
+	value(name string) (interface{}, bool)
+}
+
+// extendedDurationSource is implemented by the input sources of this package
+// which parse durations in the extended syntax of cli.ParseDuration for the
+// DurationFlags enabling it
+type extendedDurationSource interface {
+	extendedDuration(name string) (time.Duration, error)
+}
+
+// sourceString returns the named setting of the input source formatted to be
+// parsed by fl
+func sourceString(isc InputSourceContext, name string, fl cli.Flag) (string, bool, error) {
+	vs, ok := isc.(valueSource)
+	if !ok {
+		return typedSourceString(isc, name, fl)
+	}
+
+	value, ok := vs.value(name)
+	if !ok {
+		return "", false, nil
+	}
+	if _, isList := value.([]interface{}); isList {
+		return "", false, incorrectTypeForFlagError(name, "a single value", value)
+	}
+	return formatSourceValue(value), true, nil
+}
+
+// sourceStrings returns the elements of the named setting of the input source
+// formatted to be parsed by fl
+func sourceStrings(isc InputSourceContext, name string, fl cli.Flag) ([]string, error) {
+	vs, ok := isc.(valueSource)
+	if !ok {
+		return typedSourceStrings(isc, name, fl)
+	}
+
+	value, ok := vs.value(name)
+	if !ok {
+		return nil, nil
+	}
+	list, isList := value.([]interface{})
+	if !isList {
+		return nil, incorrectTypeForFlagError(name, "[]interface{}", value)
+	}
+
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		values = append(values, formatSourceValue(v))
+	}
+	return values, nil
+}
+
+// typedSourceString reads the named setting from an input source of another
+// package, through the optional interface of fl's type when the input source
+// implements it and through String otherwise
+func typedSourceString(isc InputSourceContext, name string, fl cli.Flag) (string, bool, error) {
+	switch fl.(type) {
+	case *cli.Int64Flag:
+		if s, ok := isc.(Int64InputSource); ok {
+			value, err := s.Int64(name)
+			return strconv.FormatInt(value, 10), value != 0 && err == nil, err
+		}
+	case *cli.UintFlag:
+		if s, ok := isc.(UintInputSource); ok {
+			value, err := s.Uint(name)
+			return strconv.FormatUint(uint64(value), 10), value != 0 && err == nil, err
+		}
+	case *cli.Uint64Flag:
+		if s, ok := isc.(Uint64InputSource); ok {
+			value, err := s.Uint64(name)
+			return strconv.FormatUint(value, 10), value != 0 && err == nil, err
+		}
+	}
+
+	value, err := isc.String(name)
+	return value, value != "" && err == nil, err
+}
+
+// typedSourceStrings reads the named setting from an input source of another
+// package, through the optional interface of fl's type when the input source
+// implements it and through StringSlice otherwise
+func typedSourceStrings(isc InputSourceContext, name string, fl cli.Flag) ([]string, error) {
+	switch fl.(type) {
+	case *cli.Int64SliceFlag:
+		if s, ok := isc.(Int64SliceInputSource); ok {
+			values, err := s.Int64Slice(name)
+			strs := make([]string, len(values))
+			for i, v := range values {
+				strs[i] = strconv.FormatInt(v, 10)
+			}
+			return strs, err
+		}
+	case *cli.Float64SliceFlag:
+		if s, ok := isc.(Float64SliceInputSource); ok {
+			values, err := s.Float64Slice(name)
+			strs := make([]string, len(values))
+			for i, v := range values {
+				strs[i] = strconv.FormatFloat(v, 'f', -1, 64)
+			}
+			return strs, err
+		}
+	}
+
+	return isc.StringSlice(name)
+}
+
+// formatSourceValue formats a setting of an input source to be parsed by a
+// flag, writing floats such as the numbers of JSON without an exponent and
+// times such as the dates of TOML in RFC 3339
+func formatSourceValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(value)
+}
@@ -78,6 +78,52 @@ func (x *jsonSource) Int(name string) (int, error) {
 	}
 }
 
+func (x *jsonSource) Int64(name string) (int64, error) {
+	i, err := x.getValue(name)
+	if err != nil {
+		return 0, err
+	}
+	switch v := i.(type) {
+	default:
+		return 0, fmt.Errorf("unexpected type %T for %q", i, name)
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case float64:
+		return int64(v), nil
+	}
+}
+
+func (x *jsonSource) Uint(name string) (uint, error) {
+	v, err := x.Uint64(name)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(uint(v)) != v {
+		return 0, fmt.Errorf("value %d for %q overflows uint", v, name)
+	}
+	return uint(v), nil
+}
+
+func (x *jsonSource) Uint64(name string) (uint64, error) {
+	i, err := x.getValue(name)
+	if err != nil {
+		return 0, err
+	}
+	switch v := i.(type) {
+	default:
+		return 0, fmt.Errorf("unexpected type %T for %q", i, name)
+	case uint64:
+		return v, nil
+	case float64:
+		if v < 0 {
+			return 0, fmt.Errorf("unexpected negative value %v for %q", v, name)
+		}
+		return uint64(v), nil
+	}
+}
+
 func (x *jsonSource) Duration(name string) (time.Duration, error) {
 	i, err := x.getValue(name)
 	if err != nil {
@@ -90,6 +136,20 @@ func (x *jsonSource) Duration(name string) (time.Duration, error) {
 	return v, nil
 }
 
+// extendedDuration returns a duration given as a string in the extended
+// syntax of cli.ParseDuration, such as "7d", since JSON has no durations
+func (x *jsonSource) extendedDuration(name string) (time.Duration, error) {
+	i, err := x.getValue(name)
+	if err != nil {
+		return 0, err
+	}
+	s, ok := i.(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected type %T for %q", i, name)
+	}
+	return cli.ParseDuration(s)
+}
+
 func (x *jsonSource) Float64(name string) (float64, error) {
 	i, err := x.getValue(name)
 	if err != nil {
@@ -160,6 +220,52 @@ func (x *jsonSource) IntSlice(name string) ([]int, error) {
 	}
 }
 
+func (x *jsonSource) Int64Slice(name string) ([]int64, error) {
+	i, err := x.getValue(name)
+	if err != nil {
+		return nil, err
+	}
+	switch v := i.(type) {
+	default:
+		return nil, fmt.Errorf("unexpected type %T for %q", i, name)
+	case []int64:
+		return v, nil
+	case []interface{}:
+		c := []int64{}
+		for _, s := range v {
+			if f, ok := s.(float64); ok {
+				c = append(c, int64(f))
+			} else {
+				return c, fmt.Errorf("unexpected item type %T in %T for %q", s, c, name)
+			}
+		}
+		return c, nil
+	}
+}
+
+func (x *jsonSource) Float64Slice(name string) ([]float64, error) {
+	i, err := x.getValue(name)
+	if err != nil {
+		return nil, err
+	}
+	switch v := i.(type) {
+	default:
+		return nil, fmt.Errorf("unexpected type %T for %q", i, name)
+	case []float64:
+		return v, nil
+	case []interface{}:
+		c := []float64{}
+		for _, s := range v {
+			if f, ok := s.(float64); ok {
+				c = append(c, f)
+			} else {
+				return c, fmt.Errorf("unexpected item type %T in %T for %q", s, c, name)
+			}
+		}
+		return c, nil
+	}
+}
+
 func (x *jsonSource) Generic(name string) (cli.Generic, error) {
 	i, err := x.getValue(name)
 	if err != nil {
@@ -188,6 +294,12 @@ func (x *jsonSource) getValue(key string) (interface{}, error) {
 	return jsonGetValue(key, x.deserialized)
 }
 
+// value returns the setting with the given name, whatever its type
+func (x *jsonSource) value(name string) (interface{}, bool) {
+	value, err := x.getValue(name)
+	return value, err == nil
+}
+
 func jsonGetValue(key string, m map[string]interface{}) (interface{}, error) {
 	var ret interface{}
 	var ok bool
@@ -0,0 +1,22 @@
+package altsrc
+
+import "testing"
+
+func TestJSONSourceIntegers(t *testing.T) {
+	isc, err := NewJSONSource([]byte(`{"int": 42, "negative": -1, "int64s": [1, 2], "float64s": [1, 2.5]}`))
+	expect(t, nil, err)
+	i, err := isc.(Int64InputSource).Int64("int")
+	expect(t, int64(42), i)
+	expect(t, nil, err)
+	u, err := isc.(UintInputSource).Uint("int")
+	expect(t, uint(42), u)
+	expect(t, nil, err)
+	_, err = isc.(Uint64InputSource).Uint64("negative")
+	refute(t, nil, err)
+	is, err := isc.(Int64SliceInputSource).Int64Slice("int64s")
+	expect(t, []int64{1, 2}, is)
+	expect(t, nil, err)
+	fs, err := isc.(Float64SliceInputSource).Float64Slice("float64s")
+	expect(t, []float64{1, 2.5}, fs)
+	expect(t, nil, err)
+}
@@ -2,6 +2,7 @@ package altsrc
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
 	"time"
@@ -50,6 +51,14 @@ func (fsm *MapInputSource) Source() string {
 	return fsm.file
 }
 
+// value returns the setting with the given name, whatever its type
+func (fsm *MapInputSource) value(name string) (interface{}, bool) {
+	if value, exists := fsm.valueMap[name]; exists {
+		return value, true
+	}
+	return nestedVal(name, fsm.valueMap)
+}
+
 // Int returns an int from the map if it exists otherwise returns 0
 func (fsm *MapInputSource) Int(name string) (int, error) {
 	otherGenericValue, exists := fsm.valueMap[name]
@@ -72,26 +81,111 @@ func (fsm *MapInputSource) Int(name string) (int, error) {
 	return 0, nil
 }
 
+// Int64 returns an int64 from the map if it exists otherwise returns 0
+func (fsm *MapInputSource) Int64(name string) (int64, error) {
+	otherGenericValue, exists := fsm.value(name)
+	if !exists {
+		return 0, nil
+	}
+	otherValue, isType := castInt64(otherGenericValue)
+	if !isType {
+		return 0, incorrectTypeForFlagError(name, "int64", otherGenericValue)
+	}
+	return otherValue, nil
+}
+
+// Uint returns an uint from the map if it exists otherwise returns 0
+func (fsm *MapInputSource) Uint(name string) (uint, error) {
+	otherGenericValue, exists := fsm.value(name)
+	if !exists {
+		return 0, nil
+	}
+	otherValue, isType := castUint64(otherGenericValue)
+	if !isType || uint64(uint(otherValue)) != otherValue {
+		return 0, incorrectTypeForFlagError(name, "uint", otherGenericValue)
+	}
+	return uint(otherValue), nil
+}
+
+// Uint64 returns an uint64 from the map if it exists otherwise returns 0
+func (fsm *MapInputSource) Uint64(name string) (uint64, error) {
+	otherGenericValue, exists := fsm.value(name)
+	if !exists {
+		return 0, nil
+	}
+	otherValue, isType := castUint64(otherGenericValue)
+	if !isType {
+		return 0, incorrectTypeForFlagError(name, "uint64", otherGenericValue)
+	}
+	return otherValue, nil
+}
+
+// castInt64 converts the integers decoded by the YAML and TOML parsers to
+// an int64
+func castInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	}
+	return 0, false
+}
+
+// castUint64 converts the non-negative integers decoded by the YAML and TOML
+// parsers to an uint64
+func castUint64(value interface{}) (uint64, bool) {
+	switch v := value.(type) {
+	case int:
+		return uint64(v), v >= 0
+	case int64:
+		return uint64(v), v >= 0
+	case uint:
+		return uint64(v), true
+	case uint64:
+		return v, true
+	}
+	return 0, false
+}
+
 // Duration returns a duration from the map if it exists otherwise returns 0
 func (fsm *MapInputSource) Duration(name string) (time.Duration, error) {
+	return fsm.duration(name, false)
+}
+
+// extendedDuration returns a duration in the extended syntax of
+// cli.ParseDuration from the map if it exists otherwise returns 0
+func (fsm *MapInputSource) extendedDuration(name string) (time.Duration, error) {
+	return fsm.duration(name, true)
+}
+
+func (fsm *MapInputSource) duration(name string, extended bool) (time.Duration, error) {
 	otherGenericValue, exists := fsm.valueMap[name]
 	if exists {
-		return castDuration(name, otherGenericValue)
+		return castDuration(name, otherGenericValue, extended)
 	}
 	nestedGenericValue, exists := nestedVal(name, fsm.valueMap)
 	if exists {
-		return castDuration(name, nestedGenericValue)
+		return castDuration(name, nestedGenericValue, extended)
 	}
 
 	return 0, nil
 }
 
-func castDuration(name string, value interface{}) (time.Duration, error) {
+// castDuration converts a duration or a string parsed by time.ParseDuration,
+// or by cli.ParseDuration if extended, to a duration
+func castDuration(name string, value interface{}, extended bool) (time.Duration, error) {
 	if otherValue, isType := value.(time.Duration); isType {
 		return otherValue, nil
 	}
+	parse := time.ParseDuration
+	if extended {
+		parse = cli.ParseDuration
+	}
 	otherStringValue, isType := value.(string)
-	parsedValue, err := time.ParseDuration(otherStringValue)
+	parsedValue, err := parse(otherStringValue)
 	if !isType || err != nil {
 		return 0, incorrectTypeForFlagError(name, "duration", value)
 	}
@@ -200,6 +294,62 @@ func (fsm *MapInputSource) IntSlice(name string) ([]int, error) {
 	return intSlice, nil
 }
 
+// Int64Slice returns an []int64 from the map if it exists otherwise returns nil
+func (fsm *MapInputSource) Int64Slice(name string) ([]int64, error) {
+	otherGenericValue, exists := fsm.value(name)
+	if !exists {
+		return nil, nil
+	}
+
+	otherValue, isType := otherGenericValue.([]interface{})
+	if !isType {
+		return nil, incorrectTypeForFlagError(name, "[]interface{}", otherGenericValue)
+	}
+
+	var int64Slice = make([]int64, 0, len(otherValue))
+	for i, v := range otherValue {
+		int64Value, isType := castInt64(v)
+
+		if !isType {
+			return nil, incorrectTypeForFlagError(fmt.Sprintf("%s[%d]", name, i), "int64", v)
+		}
+
+		int64Slice = append(int64Slice, int64Value)
+	}
+
+	return int64Slice, nil
+}
+
+// Float64Slice returns an []float64 from the map if it exists otherwise returns nil
+func (fsm *MapInputSource) Float64Slice(name string) ([]float64, error) {
+	otherGenericValue, exists := fsm.value(name)
+	if !exists {
+		return nil, nil
+	}
+
+	otherValue, isType := otherGenericValue.([]interface{})
+	if !isType {
+		return nil, incorrectTypeForFlagError(name, "[]interface{}", otherGenericValue)
+	}
+
+	var float64Slice = make([]float64, 0, len(otherValue))
+	for i, v := range otherValue {
+		float64Value, isType := v.(float64)
+		if !isType {
+			// lists of numbers such as [1, 2.5] mix integers and floats
+			int64Value, isInt := castInt64(v)
+			if !isInt {
+				return nil, incorrectTypeForFlagError(fmt.Sprintf("%s[%d]", name, i), "float64", v)
+			}
+			float64Value = float64(int64Value)
+		}
+
+		float64Slice = append(float64Slice, float64Value)
+	}
+
+	return float64Slice, nil
+}
+
 // Generic returns an cli.Generic from the map if it exists otherwise returns nil
 func (fsm *MapInputSource) Generic(name string) (cli.Generic, error) {
 	otherGenericValue, exists := fsm.valueMap[name]
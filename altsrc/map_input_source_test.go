@@ -22,3 +22,62 @@ func TestMapDuration(t *testing.T) {
 	_, err = inputSource.Duration("duration_of_int_type")
 	refute(t, nil, err)
 }
+
+func TestMapExtendedDuration(t *testing.T) {
+	inputSource := NewMapInputSource(
+		"test",
+		map[interface{}]interface{}{
+			"days":     "7d",
+			"iso":      "P1DT2H",
+			"standard": "1m",
+		})
+	_, err := inputSource.Duration("days")
+	refute(t, nil, err)
+	d, err := inputSource.extendedDuration("days")
+	expect(t, 7*24*time.Hour, d)
+	expect(t, nil, err)
+	d, err = inputSource.extendedDuration("iso")
+	expect(t, 26*time.Hour, d)
+	expect(t, nil, err)
+	d, err = inputSource.extendedDuration("standard")
+	expect(t, time.Minute, d)
+	expect(t, nil, err)
+}
+
+func TestMapIntegers(t *testing.T) {
+	inputSource := NewMapInputSource(
+		"test",
+		map[interface{}]interface{}{
+			"yaml_int":  42,
+			"toml_int":  int64(42),
+			"negative":  -1,
+			"int64s":    []interface{}{1, int64(2)},
+			"float64s":  []interface{}{1, 2.5},
+			"mixed_str": []interface{}{1, "2"},
+		})
+	i, err := inputSource.Int64("yaml_int")
+	expect(t, int64(42), i)
+	expect(t, nil, err)
+	i, err = inputSource.Int64("toml_int")
+	expect(t, int64(42), i)
+	expect(t, nil, err)
+	u, err := inputSource.Uint("toml_int")
+	expect(t, uint(42), u)
+	expect(t, nil, err)
+	u64, err := inputSource.Uint64("yaml_int")
+	expect(t, uint64(42), u64)
+	expect(t, nil, err)
+	_, err = inputSource.Uint64("negative")
+	refute(t, nil, err)
+	is, err := inputSource.Int64Slice("int64s")
+	expect(t, []int64{1, 2}, is)
+	expect(t, nil, err)
+	fs, err := inputSource.Float64Slice("float64s")
+	expect(t, []float64{1, 2.5}, fs)
+	expect(t, nil, err)
+	_, err = inputSource.Int64Slice("mixed_str")
+	refute(t, nil, err)
+	is, err = inputSource.Int64Slice("missing")
+	expect(t, []int64(nil), is)
+	expect(t, nil, err)
+}
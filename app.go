@@ -2,7 +2,6 @@ package cli
 
 import (
 	"context"
-	"flag"
 	"fmt"
 	"io"
 	"os"
@@ -50,8 +49,15 @@ type App struct {
 	HideHelpCommand bool
 	// Boolean to hide built-in version flag and the VERSION section of help
 	HideVersion bool
+	// HelpFlag prints the help. Defaults to the package level HelpFlag
+	HelpFlag Flag
+	// VersionFlag prints the version. Defaults to the package level VersionFlag
+	VersionFlag Flag
 	// categories contains the categorized commands and is populated on app startup
 	categories CommandCategories
+	// index maps the names of the commands to them and is populated on app
+	// startup
+	index *commandIndex
 	// An action to execute when the shell completion flag is set
 	BashComplete BashCompleteFunc
 	// An action to execute before any subcommands are run, but after the context is ready
@@ -76,12 +82,34 @@ type App struct {
 	Reader io.Reader
 	// Writer writer to write output to
 	Writer io.Writer
-	// ErrWriter writes error output
+	// ErrWriter writes error output. Defaults to the package level ErrWriter
 	ErrWriter io.Writer
+	// OsExiter is called by the default error handling to exit. Defaults to
+	// the package level OsExiter
+	OsExiter func(code int)
 	// ExitErrHandler processes any error encountered while running an App before
 	// it is returned to the caller. If no function is provided, HandleExitCoder
 	// is used as the default behavior.
 	ExitErrHandler ExitErrHandlerFunc
+	// ExitCodes maps errors to exit codes before they are passed to the
+	// ExitErrHandler and documents them in the EXIT STATUS section of help
+	// and generated docs.
+	ExitCodes ExitCodes
+	// ExitCodePolicy selects the exit code used by the default error handling
+	// if a MultiError wraps several ExitCoders, e.g. from Action and After
+	ExitCodePolicy ExitCodePolicy
+	// ErrorFormat selects how errors are written to ErrWriter. If set, the
+	// ErrorFormatFlag is added to the flags to select it on the command line.
+	ErrorFormat ErrorFormat
+	// ErrorFormatFlag selects the ErrorFormat on the command line. Defaults to
+	// the package level ErrorFormatFlag
+	ErrorFormatFlag Flag
+	// AliasPolicy selects how a flag taking a single value is handled if it
+	// is used with several of its names
+	AliasPolicy AliasPolicy
+	// Strict makes Setup validate the definitions of the App and its commands.
+	// If Validate reports mistakes, Run returns its error without running.
+	Strict bool
 	// Other custom info
 	Metadata map[string]interface{}
 	// Carries a function which returns app specific info.
@@ -90,12 +118,23 @@ type App struct {
 	// cli.go uses text/template to render templates. You can
 	// render custom help text by setting this variable.
 	CustomAppHelpTemplate string
+	// HelpPrinter writes the help output. Defaults to the package level
+	// HelpPrinter, or to HelpPrinterCustom if FlagStringer or HelpWrapAt is set
+	HelpPrinter func(w io.Writer, templ string, data interface{})
+	// FlagStringer converts a flag definition to a string in help output.
+	// Defaults to the package level FlagStringer
+	FlagStringer FlagStringFunc
+	// HelpWrapAt is the column to wrap help output at. Defaults to the
+	// package level HelpWrapAt
+	HelpWrapAt int
 	// Boolean to enable short-option handling so user can combine several
 	// single-character bool arguments into one
 	// i.e. foobar -o -v -> foobar -ov
 	UseShortOptionHandling bool
 
 	didSetup bool
+	setupErr error
+	resets   *resetFuncs
 }
 
 // Tries to find out when this binary was compiled.
@@ -121,101 +160,155 @@ func NewApp() *App {
 		Compiled:     compileTime(),
 		Reader:       os.Stdin,
 		Writer:       os.Stdout,
-		ErrWriter:    os.Stderr,
+		ErrWriter:    ErrWriter,
 	}
 }
 
 // Setup runs initialization code to ensure all data structures are ready for
 // `Run` or inspection prior to `Run`.  It is internally called by `Run`, but
-// will return early if setup has already happened.
+// will return early if setup has already happened. Use Reset to revert it.
 func (a *App) Setup() {
 	if a.didSetup {
 		return
 	}
 
 	a.didSetup = true
+	if a.resets == nil {
+		a.resets = &resetFuncs{}
+	}
 
 	if a.Name == "" {
 		a.Name = filepath.Base(os.Args[0])
+		a.onReset(func() { a.Name = "" })
 	}
 
 	if a.HelpName == "" {
 		a.HelpName = a.Name
+		a.onReset(func() { a.HelpName = "" })
 	}
 
 	if a.Usage == "" {
 		a.Usage = "A new cli application"
+		a.onReset(func() { a.Usage = "" })
 	}
 
-	if a.Version == "" {
+	if a.Version == "" && !a.HideVersion {
 		a.HideVersion = true
+		a.onReset(func() { a.HideVersion = false })
 	}
 
 	if a.BashComplete == nil {
 		a.BashComplete = DefaultAppComplete
+		a.onReset(func() { a.BashComplete = nil })
 	}
 
 	if a.Action == nil {
 		a.Action = helpCommand.Action
+		a.onReset(func() { a.Action = nil })
 	}
 
 	if a.Compiled == (time.Time{}) {
 		a.Compiled = compileTime()
+		a.onReset(func() { a.Compiled = time.Time{} })
 	}
 
 	if a.Reader == nil {
 		a.Reader = os.Stdin
+		a.onReset(func() { a.Reader = nil })
 	}
 
 	if a.Writer == nil {
 		a.Writer = os.Stdout
+		a.onReset(func() { a.Writer = nil })
 	}
 
 	if a.ErrWriter == nil {
-		a.ErrWriter = os.Stderr
+		a.ErrWriter = ErrWriter
+		a.onReset(func() { a.ErrWriter = nil })
 	}
 
-	var newCommands []*Command
-
-	for _, c := range a.Commands {
-		if c.HelpName == "" {
-			c.HelpName = fmt.Sprintf("%s %s", a.HelpName, c.Name)
-		}
-		newCommands = append(newCommands, c)
-	}
-	a.Commands = newCommands
+	a.setCommandHelpNames()
 
 	if a.Command(helpCommand.Name) == nil && !a.HideHelp {
 		if !a.HideHelpCommand {
-			a.appendCommand(helpCommand)
+			// each App gets its own copy with its own HelpName
+			helpCmd := *helpCommand
+			helpCmd.HelpName = fmt.Sprintf("%s %s", a.HelpName, helpCmd.Name)
+			a.appendCommand(&helpCmd)
 		}
 
-		if HelpFlag != nil {
-			a.appendFlag(HelpFlag)
+		if helpFlag := a.helpFlag(); helpFlag != nil {
+			a.appendFlag(helpFlag)
 		}
 	}
 
-	if !a.HideVersion {
-		a.appendFlag(VersionFlag)
+	if versionFlag := a.versionFlag(); !a.HideVersion && versionFlag != nil {
+		a.appendFlag(versionFlag)
+	}
+
+	if errorFormatFlag := a.errorFormatFlag(); a.ErrorFormat != "" && errorFormatFlag != nil {
+		a.appendFlag(errorFormatFlag)
 	}
 
+	linkCommands(a, a.Commands)
+
 	a.categories = newCommandCategories()
 	for _, command := range a.Commands {
 		a.categories.AddCommand(command.Category, command)
 	}
 	sort.Sort(a.categories.(*commandCategories))
+	a.index = newCommandIndex(a.Commands)
 
 	if a.Metadata == nil {
 		a.Metadata = make(map[string]interface{})
+		a.onReset(func() { a.Metadata = nil })
 	}
+
+	if a.Strict {
+		// lazy commands are validated once they are loaded
+		a.setupErr = a.validate(false)
+	}
+}
+
+// Reset reverts the changes Setup and previous runs made to the App and its
+// commands, such as the added help command and flags and the filled in
+// defaults. The next Run sets up the App again, so that changes made to its
+// definitions in the meantime take effect.
+func (a *App) Reset() {
+	if a.resets != nil {
+		resets := *a.resets
+		for i := len(resets) - 1; i >= 0; i-- {
+			resets[i]()
+		}
+	}
+
+	a.resets = nil
+	a.categories = nil
+	a.index = nil
+	a.setupErr = nil
+	a.didSetup = false
 }
 
-func (a *App) newFlagSet() (*flag.FlagSet, error) {
-	return flagSet(a.Name, a.Flags)
+// resetFuncs revert changes to the definitions of an App and its commands
+type resetFuncs []func()
+
+// onReset registers a function reverting a change to the App or one of its
+// commands
+func (a *App) onReset(reset func()) {
+	if a.resets != nil {
+		*a.resets = append(*a.resets, reset)
+	}
 }
 
-func (a *App) useShortOptionHandling() bool {
-	return a.UseShortOptionHandling
+// setCommandHelpNames fills in the HelpName of the commands of the App
+func (a *App) setCommandHelpNames() {
+	for _, c := range a.Commands {
+		if c.HelpName == "" {
+			c := c
+			c.HelpName = a.HelpName + " " + c.Name
+			a.onReset(func() { c.HelpName = "" })
+		}
+	}
 }
 
 // Run is the entry point to the cli app. Parses the arguments slice and routes
@@ -229,6 +322,9 @@ func (a *App) Run(arguments []string) (err error) {
 // propagate timeouts and cancellation requests
 func (a *App) RunContext(ctx context.Context, arguments []string) (err error) {
 	a.Setup()
+	if a.setupErr != nil {
+		return a.setupErr
+	}
 
 	// handle the completion flag separately from the flagset since
 	// completion could be attempted after a flag, but before its value was put
@@ -237,92 +333,11 @@ func (a *App) RunContext(ctx context.Context, arguments []string) (err error) {
 	// note that we can only do this because the shell autocomplete function
 	// always appends the completion flag at the end of the command
 	shellComplete, arguments := checkShellCompleteFlag(a, arguments)
+	errorFormat := checkErrorFormatFlag(a, arguments)
 
-	set, err := a.newFlagSet()
-	if err != nil {
-		return err
-	}
-
-	err = parseIter(set, a, arguments[1:], shellComplete)
-	nerr := normalizeFlags(a.Flags, set)
-	context := NewContext(a, set, &Context{Context: ctx})
-	if nerr != nil {
-		_, _ = fmt.Fprintln(a.Writer, nerr)
-		_ = ShowAppHelp(context)
-		return nerr
-	}
-	context.shellComplete = shellComplete
-
-	if checkCompletions(context) {
-		return nil
-	}
-
-	if err != nil {
-		if a.OnUsageError != nil {
-			err := a.OnUsageError(context, err, false)
-			a.handleExitCoder(context, err)
-			return err
-		}
-		_, _ = fmt.Fprintf(a.Writer, "%s %s\n\n", "Incorrect Usage.", err.Error())
-		_ = ShowAppHelp(context)
-		return err
-	}
-
-	if !a.HideHelp && checkHelp(context) {
-		_ = ShowAppHelp(context)
-		return nil
-	}
-
-	if !a.HideVersion && checkVersion(context) {
-		ShowVersion(context)
-		return nil
-	}
-
-	cerr := context.checkRequiredFlags(a.Flags)
-	if cerr != nil {
-		_ = ShowAppHelp(context)
-		return cerr
-	}
-
-	if a.After != nil {
-		defer func() {
-			if afterErr := a.After(context); afterErr != nil {
-				if err != nil {
-					err = newMultiError(err, afterErr)
-				} else {
-					err = afterErr
-				}
-			}
-		}()
-	}
-
-	if a.Before != nil {
-		beforeErr := a.Before(context)
-		if beforeErr != nil {
-			a.handleExitCoder(context, beforeErr)
-			err = beforeErr
-			return err
-		}
-	}
-
-	args := context.Args()
-	if args.Present() {
-		name := args.First()
-		c := a.Command(name)
-		if c != nil {
-			return c.Run(context)
-		}
-	}
-
-	if a.Action == nil {
-		a.Action = helpCommand.Action
-	}
-
-	// Run default Action
-	err = a.Action(context)
-
-	a.handleExitCoder(context, err)
-	return err
+	root := a.asCommand()
+	root.isRoot = true
+	return root.run(a, &Context{Context: ctx, shellComplete: shellComplete, errorFormat: errorFormat}, arguments)
 }
 
 // RunAndExitOnError calls .Run() and exits non-zero if an error was returned
@@ -333,124 +348,107 @@ func (a *App) RunContext(ctx context.Context, arguments []string) (err error) {
 func (a *App) RunAndExitOnError() {
 	if err := a.Run(os.Args); err != nil {
 		_, _ = fmt.Fprintln(a.ErrWriter, err)
-		OsExiter(1)
+		a.osExiter()(1)
 	}
 }
 
 // RunAsSubcommand invokes the subcommand given the context, parses ctx.Args() to
 // generate command-specific flags
 func (a *App) RunAsSubcommand(ctx *Context) (err error) {
-	// Setup also handles HideHelp and HideHelpCommand
 	a.Setup()
-
-	var newCmds []*Command
-	for _, c := range a.Commands {
-		if c.HelpName == "" {
-			c.HelpName = fmt.Sprintf("%s %s", a.HelpName, c.Name)
-		}
-		newCmds = append(newCmds, c)
+	if a.setupErr != nil {
+		return a.setupErr
 	}
-	a.Commands = newCmds
+	return a.asCommand().run(a, ctx, ctx.Args().Slice())
+}
 
-	set, err := a.newFlagSet()
-	if err != nil {
-		return err
+// asCommand returns a Command with the definitions of the App, so that the
+// App runs the same way as the commands beneath it
+func (a *App) asCommand() *Command {
+	return &Command{
+		Name:                   a.Name,
+		HelpName:               a.HelpName,
+		Usage:                  a.Usage,
+		UsageText:              a.UsageText,
+		Description:            a.Description,
+		ArgsUsage:              a.ArgsUsage,
+		BashComplete:           a.BashComplete,
+		Before:                 a.Before,
+		After:                  a.After,
+		Action:                 a.Action,
+		OnUsageError:           a.OnUsageError,
+		Subcommands:            a.Commands,
+		Flags:                  a.Flags,
+		HideHelp:               a.HideHelp,
+		HideHelpCommand:        a.HideHelpCommand,
+		UseShortOptionHandling: a.UseShortOptionHandling,
+		CustomHelpTemplate:     a.CustomAppHelpTemplate,
+		categories:             a.categories,
+		index:                  a.index,
 	}
+}
 
-	err = parseIter(set, a, ctx.Args().Tail(), ctx.shellComplete)
-	nerr := normalizeFlags(a.Flags, set)
-	context := NewContext(a, set, ctx)
+// Command returns the named command on App. Returns nil if the command does not exist
+func (a *App) Command(name string) *Command {
+	return a.index.lookup(a.Commands, name)
+}
 
-	if nerr != nil {
-		_, _ = fmt.Fprintln(a.Writer, nerr)
-		_, _ = fmt.Fprintln(a.Writer)
-		if len(a.Commands) > 0 {
-			_ = ShowSubcommandHelp(context)
-		} else {
-			_ = ShowCommandHelp(ctx, context.Args().First())
-		}
-		return nerr
-	}
+// WalkFunc is called by App.Walk for each command with the names of the
+// command and its parent commands
+type WalkFunc func(path []string, cmd *Command) error
 
-	if checkCompletions(context) {
-		return nil
-	}
+// Walk calls fn for each command of the App and their subcommands, parents
+// before their subcommands, until fn returns an error. The App is set up
+// first, so that the help commands are included, and each command is loaded
+// before it is visited.
+func (a *App) Walk(fn WalkFunc) error {
+	a.Setup()
+	return a.walkCommands(nil, a.Commands, fn, true)
+}
 
-	if err != nil {
-		if a.OnUsageError != nil {
-			err = a.OnUsageError(context, err, true)
-			a.handleExitCoder(context, err)
-			return err
+// walkCommands walks the commands and their subcommands, skipping the
+// commands which have not been loaded yet unless load is set
+func (a *App) walkCommands(path []string, commands []*Command, fn WalkFunc, load bool) error {
+	for _, c := range commands {
+		if !load && !c.isLoaded() {
+			continue
 		}
-		_, _ = fmt.Fprintf(a.Writer, "%s %s\n\n", "Incorrect Usage.", err.Error())
-		_ = ShowSubcommandHelp(context)
-		return err
-	}
-
-	if len(a.Commands) > 0 {
-		if checkSubcommandHelp(context) {
-			return nil
+		if err := c.loadAndSetup(a); err != nil {
+			return err
 		}
-	} else {
-		if checkCommandHelp(ctx, context.Args().First()) {
-			return nil
+		// every call gets its own path, which fn may keep
+		path := append(path[:len(path):len(path)], c.Name)
+		if err := fn(path, c); err != nil {
+			return err
 		}
-	}
-
-	cerr := context.checkRequiredFlags(a.Flags)
-	if cerr != nil {
-		_ = ShowSubcommandHelp(context)
-		return cerr
-	}
-
-	if a.After != nil {
-		defer func() {
-			afterErr := a.After(context)
-			if afterErr != nil {
-				a.handleExitCoder(context, err)
-				if err != nil {
-					err = newMultiError(err, afterErr)
-				} else {
-					err = afterErr
-				}
-			}
-		}()
-	}
-
-	if a.Before != nil {
-		beforeErr := a.Before(context)
-		if beforeErr != nil {
-			a.handleExitCoder(context, beforeErr)
-			err = beforeErr
+		if err := a.walkCommands(path, c.Subcommands, fn, load); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	args := context.Args()
-	if args.Present() {
-		name := args.First()
-		c := a.Command(name)
-		if c != nil {
-			return c.Run(context)
-		}
+// FindCommand returns the command reached by following the path of command
+// names or aliases from the commands of the App, e.g. FindCommand("a", "b")
+// for the command run by "app a b". The commands on the path are loaded.
+// Returns nil if there is no such command or it cannot be loaded.
+func (a *App) FindCommand(path ...string) *Command {
+	a.Setup()
+	if len(path) == 0 {
+		return nil
 	}
 
-	// Run default Action
-	err = a.Action(context)
-
-	a.handleExitCoder(context, err)
-	return err
-}
-
-// Command returns the named command on App. Returns nil if the command does not exist
-func (a *App) Command(name string) *Command {
-	for _, c := range a.Commands {
-		if c.HasName(name) {
-			return c
+	c := a.Command(path[0])
+	for _, name := range path[1:] {
+		if c == nil || c.loadAndSetup(a) != nil {
+			return nil
 		}
+		c = c.Command(name)
 	}
-
-	return nil
+	if c == nil || c.loadAndSetup(a) != nil {
+		return nil
+	}
+	return c
 }
 
 // VisibleCategories returns a slice of categories and commands that are
@@ -486,24 +484,93 @@ func (a *App) VisibleFlags() []Flag {
 	return visibleFlags(a.Flags)
 }
 
+// VisibleExitCodes returns the ExitCodes which have a description
+func (a *App) VisibleExitCodes() ExitCodes {
+	return a.ExitCodes.Documented()
+}
+
 func (a *App) appendFlag(fl Flag) {
 	if !hasFlag(a.Flags, fl) {
-		a.Flags = append(a.Flags, fl)
+		a.Flags = appendFlag(a.Flags, fl)
+		a.onReset(func() { a.Flags = removeFlag(a.Flags, fl) })
 	}
 }
 
 func (a *App) appendCommand(c *Command) {
 	if !hasCommand(a.Commands, c) {
-		a.Commands = append(a.Commands, c)
+		// never write to the backing array of the Commands passed in
+		a.Commands = append(a.Commands[:len(a.Commands):len(a.Commands)], c)
+		a.onReset(func() { a.Commands = removeCommand(a.Commands, c) })
 	}
 }
 
 func (a *App) handleExitCoder(context *Context, err error) {
+	err = a.ExitCodes.apply(err)
 	if a.ExitErrHandler != nil {
 		a.ExitErrHandler(context, err)
+	} else if context != nil && context.errorFormat == ErrorFormatJSON {
+		a.handleExitCoderJSON(context, err)
 	} else {
-		HandleExitCoder(err)
+		handleExitCoder(err, a.ExitCodePolicy, a.errWriter(), a.osExiter())
+	}
+}
+
+// handleUsageError passes a usage error on like handleExitCoder if ExitCodes
+// are set, which may map it to an exit code. Without ExitCodes usage errors
+// are only returned, and the ExitErrHandler is not called for them.
+func (a *App) handleUsageError(context *Context, err error) {
+	if len(a.ExitCodes) > 0 {
+		a.handleExitCoder(context, err)
+	}
+}
+
+func (a *App) errWriter() io.Writer {
+	if a.ErrWriter != nil {
+		return a.ErrWriter
+	}
+	return ErrWriter
+}
+
+func (a *App) osExiter() func(code int) {
+	if a.OsExiter != nil {
+		return a.OsExiter
+	}
+	return OsExiter
+}
+
+func (a *App) helpFlag() Flag {
+	if a.HelpFlag != nil {
+		return a.HelpFlag
+	}
+	return HelpFlag
+}
+
+func (a *App) versionFlag() Flag {
+	if a.VersionFlag != nil {
+		return a.VersionFlag
+	}
+	return VersionFlag
+}
+
+func (a *App) errorFormatFlag() Flag {
+	if a.ErrorFormatFlag != nil {
+		return a.ErrorFormatFlag
+	}
+	return ErrorFormatFlag
+}
+
+func (a *App) flagStringer() FlagStringFunc {
+	if a.FlagStringer != nil {
+		return a.FlagStringer
+	}
+	return FlagStringer
+}
+
+func (a *App) helpWrapAt() int {
+	if a.HelpWrapAt != 0 {
+		return a.HelpWrapAt
 	}
+	return HelpWrapAt
 }
 
 // Author represents someone who has contributed to a cli project.
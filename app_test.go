@@ -10,7 +10,9 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 var (
@@ -175,10 +177,13 @@ func ExampleApp_Run_commandHelp() {
 	//    greet describeit - use it to see a description
 	//
 	// USAGE:
-	//    greet describeit [arguments...]
+	//    greet describeit [command options] [arguments...]
 	//
 	// DESCRIPTION:
 	//    This is how we describe describeit the function
+	//
+	// OPTIONS:
+	//    --help, -h  Show help
 }
 
 func ExampleApp_Run_noAction() {
@@ -432,6 +437,30 @@ func TestApp_Command(t *testing.T) {
 	}
 }
 
+func TestApp_Command_Index(t *testing.T) {
+	first := &Command{Name: "foobar", Aliases: []string{"f"}}
+	app := &App{
+		Commands: []*Command{
+			first,
+			{Name: "f"},
+		},
+	}
+	app.Setup()
+
+	// the first command with a name wins, as in the order of Commands
+	expect(t, app.Command("f"), first)
+	expect(t, app.Command("nothing"), (*Command)(nil))
+
+	// commands added after Setup are found without an index
+	added := &Command{Name: "added"}
+	app.Commands = append(app.Commands, added)
+	expect(t, app.Command("added"), added)
+
+	app.Reset()
+	app.Setup()
+	expect(t, app.Command("added"), added)
+}
+
 func TestApp_Setup_defaultsReader(t *testing.T) {
 	app := &App{}
 	app.Setup()
@@ -645,7 +674,7 @@ func TestApp_UseShortOptionHandling_missing_value(t *testing.T) {
 	}
 
 	err := app.Run([]string{"", "-n"})
-	expect(t, err, errors.New("flag needs an argument: -n"))
+	expect(t, err, &MissingValueError{Name: "n", Arg: "-n"})
 }
 
 func TestApp_UseShortOptionHandlingCommand(t *testing.T) {
@@ -689,7 +718,7 @@ func TestApp_UseShortOptionHandlingCommand_missing_value(t *testing.T) {
 	app.Commands = []*Command{command}
 
 	err := app.Run([]string{"", "cmd", "-n"})
-	expect(t, err, errors.New("flag needs an argument: -n"))
+	expect(t, err, &MissingValueError{Name: "n", Arg: "-n"})
 }
 
 func TestApp_UseShortOptionHandlingSubCommand(t *testing.T) {
@@ -742,7 +771,7 @@ func TestApp_UseShortOptionHandlingSubCommand_missing_value(t *testing.T) {
 	app.Commands = []*Command{command}
 
 	err := app.Run([]string{"", "cmd", "sub", "-n"})
-	expect(t, err, errors.New("flag needs an argument: -n"))
+	expect(t, err, &MissingValueError{Name: "n", Arg: "-n"})
 }
 
 func TestApp_Float64Flag(t *testing.T) {
@@ -1614,12 +1643,12 @@ func TestApp_Run_CommandHelpName(t *testing.T) {
 
 	output := buf.String()
 
-	expected := "command foo bar - does bar things"
+	expected := "custom bar - does bar things"
 	if !strings.Contains(output, expected) {
 		t.Errorf("expected %q in output: %s", expected, output)
 	}
 
-	expected = "command foo bar [command options] [arguments...]"
+	expected = "custom bar [command options] [arguments...]"
 	if !strings.Contains(output, expected) {
 		t.Errorf("expected %q in output: %s", expected, output)
 	}
@@ -2228,8 +2257,8 @@ func TestSetupInitializesBothWriters(t *testing.T) {
 
 	a.Setup()
 
-	if a.ErrWriter != os.Stderr {
-		t.Errorf("expected a.ErrWriter to be os.Stderr")
+	if a.ErrWriter != ErrWriter {
+		t.Errorf("expected a.ErrWriter to be the package level ErrWriter")
 	}
 
 	if a.Writer != os.Stdout {
@@ -2346,3 +2375,486 @@ func equal(actualOutputLines, expectedOutputLines []string, t *testing.T) bool {
 	}
 	return true
 }
+
+func TestApp_RunConcurrently(t *testing.T) {
+	defer resetEnv(os.Environ())
+	_ = os.Setenv("APP_CONCURRENT_NAME", "from-env")
+
+	newApp := func(writer, errWriter io.Writer, exitCode *int) *App {
+		return &App{
+			Name:       "concurrent",
+			Writer:     writer,
+			ErrWriter:  errWriter,
+			OsExiter:   func(code int) { *exitCode = code },
+			HelpWrapAt: 40,
+			FlagStringer: func(f Flag) string {
+				return "flag:" + f.Names()[0] + "\t"
+			},
+			Flags: []Flag{
+				&StringFlag{Name: "name", EnvVars: []string{"APP_CONCURRENT_NAME"}},
+				&StringSliceFlag{Name: "tag", Value: NewStringSlice("default")},
+			},
+			Commands: []*Command{
+				{
+					Name: "check",
+					Action: func(c *Context) error {
+						if !c.IsSet("name") {
+							return errors.New("expected name to be set from the environment")
+						}
+						_, _ = fmt.Fprintln(c.App.Writer, c.StringSlice("tag"))
+						return nil
+					},
+				},
+				{
+					Name: "fail",
+					Action: func(*Context) error {
+						return Exit("failed", 3)
+					},
+				},
+			},
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var output, errOutput bytes.Buffer
+			exitCode := -1
+			tag := fmt.Sprintf("tag%d", i)
+
+			err := newApp(&output, &errOutput, &exitCode).Run([]string{"concurrent", "--tag", tag, "check"})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if output.String() != "["+tag+"]\n" {
+				t.Errorf("expected output %q, got %q", "["+tag+"]\n", output.String())
+			}
+
+			output.Reset()
+			_ = newApp(&output, &errOutput, &exitCode).Run([]string{"concurrent", "--help"})
+			if !strings.Contains(output.String(), "flag:name") {
+				t.Errorf("expected help to use the FlagStringer of the App, got:\n%s", output.String())
+			}
+
+			_ = newApp(&output, &errOutput, &exitCode).Run([]string{"concurrent", "fail"})
+			if errOutput.String() != "failed\n" || exitCode != 3 {
+				t.Errorf("expected exit code 3 and %q, got %d and %q", "failed\n", exitCode, errOutput.String())
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestApp_RunSharedConcurrently(t *testing.T) {
+	app := newDepthApp(ioutil.Discard)
+	// the commands are set up by the first run reaching them
+	app.Setup()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			args := []string{"app", "cmd", "sub", "--bottom", fmt.Sprint(i)}
+			if i%2 == 1 {
+				args = []string{"app", "cmd", "--help"}
+			}
+			if err := app.Run(args); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestApp_PerAppSettings(t *testing.T) {
+	var output bytes.Buffer
+	helpFlag := &BoolFlag{Name: "assist", Aliases: []string{"a"}}
+	versionFlag := &BoolFlag{Name: "show-version"}
+	printed := ""
+
+	app := &App{
+		Name:        "app",
+		Version:     "1.2.3",
+		Writer:      &output,
+		HelpFlag:    helpFlag,
+		VersionFlag: versionFlag,
+		HelpPrinter: func(_ io.Writer, templ string, _ interface{}) {
+			printed = templ
+		},
+		Commands: []*Command{
+			{Name: "cmd", Action: func(*Context) error { return nil }},
+		},
+	}
+
+	expect(t, app.Run([]string{"app", "--assist"}), nil)
+	expect(t, printed, AppHelpTemplate)
+
+	printed = ""
+	expect(t, app.Run([]string{"app", "cmd", "-a"}), nil)
+	expect(t, printed, CommandHelpTemplate)
+
+	expect(t, app.Run([]string{"app", "--show-version"}), nil)
+	expect(t, output.String(), "app version 1.2.3\n")
+
+	expect(t, HelpFlag.Names(), []string{"help", "h"})
+	expect(t, VersionFlag.Names(), []string{"version", "V"})
+}
+
+func TestApp_RunTwice(t *testing.T) {
+	var (
+		tags   StringSlice
+		when   Timestamp
+		name   string
+		output bytes.Buffer
+		runs   []string
+	)
+
+	app := &App{
+		Name:   "app",
+		Writer: &output,
+		Flags: []Flag{
+			&StringSliceFlag{Name: "tag", Value: NewStringSlice("default"), Destination: &tags},
+			&TimestampFlag{Name: "when", Layout: "2006-01-02", Destination: &when},
+			&StringFlag{Name: "name", Value: "anonymous", Destination: &name},
+		},
+		Commands: []*Command{
+			{
+				Name:  "sub",
+				Flags: []Flag{&IntSliceFlag{Name: "n", Value: NewIntSlice(1)}},
+				Subcommands: []*Command{
+					{
+						Name:  "leaf",
+						Flags: []Flag{&Int64SliceFlag{Name: "m"}},
+						Action: func(c *Context) error {
+							runs = append(runs, fmt.Sprint(c.IntSlice("n"), c.Int64Slice("m")))
+							return nil
+						},
+					},
+				},
+			},
+		},
+		Action: func(c *Context) error {
+			runs = append(runs, fmt.Sprint(tags.Value(), when.Value(), name))
+			return nil
+		},
+	}
+
+	run := func() []string {
+		runs = nil
+		for _, args := range [][]string{
+			{"app", "--tag", "a", "--tag", "b", "--when", "2020-01-01", "--name", "x"},
+			{"app"},
+			{"app", "sub", "-n", "2", "leaf", "-m", "3"},
+			{"app", "sub", "-n", "4", "leaf"},
+			{"app", "--help"},
+			{"app", "sub", "--help"},
+			{"app", "sub", "leaf", "--help"},
+		} {
+			output.Reset()
+			if err := app.Run(args); err != nil {
+				t.Fatalf("unexpected error for %v: %v", args, err)
+			}
+			runs = append(runs, output.String())
+		}
+		return runs
+	}
+
+	first := run()
+	expectedWhen := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	expect(t, first[0], fmt.Sprint([]string{"a", "b"}, &expectedWhen, "x"))
+	expect(t, first[2], fmt.Sprint([]string{"default"}, (*time.Time)(nil), "anonymous"))
+	expect(t, run(), first)
+}
+
+func TestApp_RunTwice_SliceDestinationWithoutValue(t *testing.T) {
+	var tags StringSlice
+	app := &App{
+		Name:   "app",
+		Writer: ioutil.Discard,
+		Flags:  []Flag{&StringSliceFlag{Name: "tag", Destination: &tags}},
+		Action: func(*Context) error { return nil },
+	}
+
+	expect(t, app.Run([]string{"app", "--tag", "a", "--tag", "b"}), nil)
+	expect(t, tags.Value(), []string{"a", "b"})
+
+	expect(t, app.Run([]string{"app"}), nil)
+	expect(t, tags.Value(), []string{})
+}
+
+func TestApp_Setup_KeepsDefinitions(t *testing.T) {
+	cmd := &Command{Name: "cmd", Action: func(*Context) error { return nil }}
+	commands := make([]*Command, 1, 10)
+	commands[0] = cmd
+	flags := make([]Flag, 1, 10)
+	flags[0] = &StringFlag{Name: "s"}
+
+	app := &App{Writer: ioutil.Discard, Commands: commands, Flags: flags}
+	expect(t, app.Run([]string{"app", "cmd"}), nil)
+
+	expect(t, commands[:cap(commands)][1], (*Command)(nil))
+	expect(t, flags[:cap(flags)][1], nil)
+	expect(t, len(app.Commands), 2)
+	expect(t, len(app.Flags), 2)
+}
+
+func TestApp_Reset(t *testing.T) {
+	sub := &Command{Name: "sub", Action: func(*Context) error { return nil }}
+	cmd := &Command{Name: "cmd", Subcommands: []*Command{sub}}
+	flag := &StringFlag{Name: "s"}
+	app := &App{
+		Name:     "app",
+		Writer:   ioutil.Discard,
+		Commands: []*Command{cmd},
+		Flags:    []Flag{flag},
+	}
+
+	expect(t, app.Run([]string{"app", "cmd", "sub"}), nil)
+	resets := len(*app.resets)
+	for i := 0; i < 2; i++ {
+		expect(t, app.Run([]string{"app", "cmd", "sub"}), nil)
+	}
+	expect(t, cmd.HelpName, "app cmd")
+	expect(t, len(*app.resets), resets)
+
+	app.Reset()
+
+	expect(t, app.Commands, []*Command{cmd})
+	expect(t, app.Flags, []Flag{flag})
+	expect(t, app.HelpName, "")
+	expect(t, app.Usage, "")
+	expect(t, app.Action == nil, true)
+	expect(t, app.ErrWriter, nil)
+	expect(t, cmd.HelpName, "")
+	expect(t, sub.HelpName, "")
+	expect(t, len(sub.Flags), 0)
+
+	app.Name = "renamed"
+	output := &bytes.Buffer{}
+	app.Writer = output
+	expect(t, app.Run([]string{"renamed", "cmd", "sub", "--help"}), nil)
+	if !strings.Contains(output.String(), "renamed cmd sub") {
+		t.Errorf("expected help to use the new name, got:\n%s", output.String())
+	}
+}
+
+// newDepthApp returns an App with a command and a subcommand beneath it, each
+// defining a flag of its own
+func newDepthApp(output io.Writer) *App {
+	return &App{
+		Name:     "app",
+		Writer:   output,
+		OsExiter: func(int) {},
+		Flags:    []Flag{&BoolFlag{Name: "top"}},
+		Commands: []*Command{
+			{
+				Name:  "cmd",
+				Usage: "runs cmd",
+				Flags: []Flag{&BoolFlag{Name: "middle"}},
+				Subcommands: []*Command{
+					{
+						Name:   "sub",
+						Usage:  "runs sub",
+						Flags:  []Flag{&BoolFlag{Name: "bottom"}},
+						Action: func(*Context) error { return nil },
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestApp_Run_HelpAtEveryDepth(t *testing.T) {
+	cases := []struct {
+		expected string
+		args     [][]string
+	}{
+		{
+			expected: "app cmd - runs cmd",
+			args: [][]string{
+				{"app", "cmd", "--help"},
+				{"app", "cmd", "help"},
+				{"app", "help", "cmd"},
+				{"app", "cmd"},
+			},
+		},
+		{
+			expected: "app cmd sub - runs sub",
+			args: [][]string{
+				{"app", "cmd", "sub", "--help"},
+				{"app", "cmd", "help", "sub"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		var first string
+		for i, args := range c.args {
+			output := &bytes.Buffer{}
+			expect(t, newDepthApp(output).Run(args), nil)
+
+			if !strings.Contains(output.String(), c.expected) {
+				t.Errorf("%q: expected %q in output:\n%s", args, c.expected, output.String())
+			}
+			if i == 0 {
+				first = output.String()
+			} else if output.String() != first {
+				t.Errorf("%q: expected the same help as for %q, got:\n%s", args, c.args[0], output.String())
+			}
+		}
+	}
+}
+
+func TestApp_Run_UsageErrorAtEveryDepth(t *testing.T) {
+	cases := []struct {
+		args         []string
+		helpName     string
+		isSubcommand bool
+	}{
+		{args: []string{"app", "--bottom"}, helpName: "app"},
+		{args: []string{"app", "cmd", "--bottom"}, helpName: "app cmd", isSubcommand: true},
+		{args: []string{"app", "cmd", "sub", "--top"}, helpName: "app cmd sub", isSubcommand: false},
+	}
+
+	for _, c := range cases {
+		output := &bytes.Buffer{}
+		app := newDepthApp(output)
+		err := app.Run(c.args)
+		if err == nil {
+			t.Fatalf("%q: expected a usage error", c.args)
+		}
+
+		expected := fmt.Sprintf("Incorrect Usage. %s\n\nNAME:\n   %s - ", err, c.helpName)
+		if !strings.HasPrefix(output.String(), expected) {
+			t.Errorf("%q: expected output to start with %q, got:\n%s", c.args, expected, output.String())
+		}
+
+		var isSubcommand *bool
+		onUsageError := func(_ *Context, err error, sub bool) error {
+			isSubcommand = &sub
+			return err
+		}
+		app = newDepthApp(ioutil.Discard)
+		app.OnUsageError = onUsageError
+		app.Commands[0].OnUsageError = onUsageError
+		app.Commands[0].Subcommands[0].OnUsageError = onUsageError
+		_ = app.Run(c.args)
+		if isSubcommand == nil || *isSubcommand != c.isSubcommand {
+			t.Errorf("%q: expected OnUsageError to be called with isSubcommand=%v", c.args, c.isSubcommand)
+		}
+	}
+}
+
+func TestApp_Run_CompletionAtEveryDepth(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"app", "--generate-bash-completion"}, expected: "app"},
+		{args: []string{"app", "cmd", "--generate-bash-completion"}, expected: "cmd"},
+		{args: []string{"app", "cmd", "sub", "--generate-bash-completion"}, expected: "sub"},
+	}
+
+	for _, c := range cases {
+		output := &bytes.Buffer{}
+		app := newDepthApp(output)
+		app.EnableBashCompletion = true
+		complete := func(ctx *Context) {
+			_, _ = fmt.Fprint(ctx.App.Writer, ctx.Command.Name)
+		}
+		app.BashComplete = complete
+		app.Commands[0].BashComplete = complete
+		app.Commands[0].Subcommands[0].BashComplete = complete
+
+		expect(t, app.Run(c.args), nil)
+		expect(t, output.String(), c.expected)
+	}
+}
+
+func TestApp_Run_ContextAtEveryDepth(t *testing.T) {
+	app := newDepthApp(ioutil.Discard)
+	cmd := app.Commands[0]
+	sub := cmd.Subcommands[0]
+
+	var lineage []*Context
+	sub.Action = func(c *Context) error {
+		lineage = c.Lineage()
+		return nil
+	}
+
+	expect(t, app.Run([]string{"app", "--top", "cmd", "--middle", "sub", "--bottom"}), nil)
+	if len(lineage) < 3 {
+		t.Fatalf("expected a context for each depth, got %d", len(lineage))
+	}
+	for i, command := range []*Command{sub, cmd} {
+		expect(t, lineage[i].App, app)
+		expect(t, lineage[i].Command, command)
+	}
+	expect(t, lineage[2].App, app)
+	expect(t, lineage[2].Command.Name, "app")
+	expect(t, lineage[0].Bool("bottom"), true)
+	expect(t, lineage[0].Bool("middle"), true)
+	expect(t, lineage[0].Bool("top"), true)
+}
+
+func TestApp_Walk(t *testing.T) {
+	app := newDepthApp(ioutil.Discard)
+	app.HideHelpCommand = true
+	app.Commands[0].HideHelpCommand = true
+
+	var visited []string
+	err := app.Walk(func(path []string, cmd *Command) error {
+		expect(t, path[len(path)-1], cmd.Name)
+		expect(t, cmd.FullName(), strings.Join(path, " "))
+		visited = append(visited, strings.Join(path, " "))
+		return nil
+	})
+	expect(t, err, nil)
+	expect(t, visited, []string{"cmd", "cmd sub"})
+
+	stop := errors.New("stop")
+	visited = nil
+	err = app.Walk(func(path []string, cmd *Command) error {
+		visited = append(visited, strings.Join(path, " "))
+		return stop
+	})
+	expect(t, err, stop)
+	expect(t, visited, []string{"cmd"})
+}
+
+func TestApp_Walk_KeepsPaths(t *testing.T) {
+	app := &App{
+		HideHelp: true,
+		Commands: []*Command{
+			{Name: "a", Subcommands: []*Command{{Name: "b"}, {Name: "c"}}, HideHelp: true},
+		},
+	}
+
+	var paths [][]string
+	_ = app.Walk(func(path []string, cmd *Command) error {
+		paths = append(paths, path)
+		return nil
+	})
+	expect(t, paths, [][]string{{"a"}, {"a", "b"}, {"a", "c"}})
+}
+
+func TestApp_FindCommand(t *testing.T) {
+	app := newDepthApp(ioutil.Discard)
+	cmd := app.Commands[0]
+	sub := cmd.Subcommands[0]
+	sub.Aliases = []string{"s"}
+
+	expect(t, app.FindCommand("cmd"), cmd)
+	expect(t, app.FindCommand("cmd", "sub"), sub)
+	expect(t, app.FindCommand("cmd", "s"), sub)
+	expect(t, app.FindCommand("cmd", "help").HelpName, "app cmd help")
+	expect(t, app.FindCommand(), (*Command)(nil))
+	expect(t, app.FindCommand("sub"), (*Command)(nil))
+	expect(t, app.FindCommand("cmd", "sub", "more"), (*Command)(nil))
+	expect(t, app.FindCommand("cmd", "missing", "sub"), (*Command)(nil))
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	benchmarkCommands        = 1000
+	benchmarkFlagsPerCommand = 10
+)
+
+// newBenchmarkApp returns an App with 1,000 commands of 10 flags each, which
+// is 10,000 flags in total
+func newBenchmarkApp() *App {
+	app := &App{
+		Name:   "bench",
+		Writer: ioutil.Discard,
+		Flags: []Flag{
+			&BoolFlag{Name: "verbose", Usage: "print more"},
+		},
+	}
+
+	for i := 0; i < benchmarkCommands; i++ {
+		cmd := &Command{
+			Name:    fmt.Sprintf("command-%d", i),
+			Aliases: []string{fmt.Sprintf("c%d", i)},
+			Usage:   fmt.Sprintf("run command %d", i),
+			Action:  func(*Context) error { return nil },
+		}
+		for j := 0; j < benchmarkFlagsPerCommand; j++ {
+			var f Flag
+			switch j % 3 {
+			case 0:
+				f = &StringFlag{Name: fmt.Sprintf("string-%d", j), Usage: "a string", Value: "value"}
+			case 1:
+				f = &IntFlag{Name: fmt.Sprintf("int-%d", j), Usage: "an int", Value: j}
+			default:
+				f = &BoolFlag{Name: fmt.Sprintf("bool-%d", j), Usage: "a bool", EnvVars: []string{"BENCH_BOOL"}}
+			}
+			cmd.Flags = append(cmd.Flags, f)
+		}
+		app.Commands = append(app.Commands, cmd)
+	}
+
+	return app
+}
+
+// BenchmarkApp_Startup measures setting up and running an App once, as a
+// process does on each invocation
+func BenchmarkApp_Startup(b *testing.B) {
+	args := []string{"bench", "command-999", "--string-0", "x"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		app := newBenchmarkApp()
+		b.StartTimer()
+
+		if err := app.Run(args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkApp_Setup(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		app := newBenchmarkApp()
+		b.StartTimer()
+
+		app.Setup()
+	}
+}
+
+func BenchmarkApp_Run(b *testing.B) {
+	app := newBenchmarkApp()
+	args := []string{"bench", "--verbose", "c999", "--string-0", "x", "--int-1", "2"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := app.Run(args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkApp_Command(b *testing.B) {
+	app := newBenchmarkApp()
+	app.Setup()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if app.Command("c999") == nil {
+			b.Fatal("command not found")
+		}
+	}
+}
+
+func BenchmarkApp_Run_Help(b *testing.B) {
+	app := newBenchmarkApp()
+	args := []string{"bench", "command-500", "--help"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := app.Run(args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFlagStringer(b *testing.B) {
+	flags := newBenchmarkApp().Commands[0].Flags
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, f := range flags {
+			_ = FlagStringer(f)
+		}
+	}
+}
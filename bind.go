@@ -0,0 +1,465 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+
+	// scalarTypes are the types of the flags of fields by their kind
+	scalarTypes = map[reflect.Kind]reflect.Type{
+		reflect.String:  reflect.TypeOf(""),
+		reflect.Bool:    reflect.TypeOf(false),
+		reflect.Int:     reflect.TypeOf(0),
+		reflect.Int64:   reflect.TypeOf(int64(0)),
+		reflect.Uint:    reflect.TypeOf(uint(0)),
+		reflect.Uint64:  reflect.TypeOf(uint64(0)),
+		reflect.Float64: reflect.TypeOf(float64(0)),
+	}
+)
+
+// StructField is a flag generated for a field of a struct by StructFields
+type StructField struct {
+	Flag Flag
+	// Key is the key of the field in an input source, as used by altsrc. It
+	// is given by the altsrc tag of the field, and defaults to its flag name,
+	// joined with dots to the keys of the structs it is nested in.
+	Key string
+}
+
+// FlagsFromStruct returns the flags of the exported fields of the struct v
+// points to. The flags set the fields when the command line is parsed, and
+// the values the fields have beforehand are their default values.
+//
+// The flag of a field is described by its tags:
+//
+//	cli:"name,alias=n"    the name and aliases, "-" to skip the field
+//	usage:"..."           the usage text
+//	env:"APP_NAME"        the environment variables, separated by commas
+//	required:"true"       whether the flag is required
+//	hidden:"true"         whether the flag is hidden
+//	placeholder:"NAME"    the placeholder of the value in help
+//	choices:"a,b,c"       the values allowed for a string field
+//	altsrc:"key"          the key of the field in an input source
+//
+// Fields without a name are named by their Go name in kebab case. The fields
+// of a nested struct are named with the name of the struct field as a prefix,
+// and the env tag of the struct field is the prefix of their environment
+// variables. The fields of embedded structs take no prefix.
+//
+// Supported are fields of type string, bool, int, int64, uint, uint64,
+// float64 and time.Duration, named types based on these, slices of these and
+// maps of them with string keys, set as "key=value".
+func FlagsFromStruct(v interface{}) ([]Flag, error) {
+	fields, err := StructFields(v)
+	if err != nil {
+		return nil, err
+	}
+	flags := make([]Flag, len(fields))
+	for i, field := range fields {
+		flags[i] = field.Flag
+	}
+	return flags, nil
+}
+
+// StructFields is like FlagsFromStruct, but also returns the keys of the
+// fields in an input source
+func StructFields(v interface{}) ([]StructField, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a pointer to a struct, got %T", v)
+	}
+	return structFields(rv.Elem(), "", "", "")
+}
+
+func structFields(sv reflect.Value, namePrefix, envPrefix, keyPrefix string) ([]StructField, error) {
+	var fields []StructField
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+
+		name, aliases := parseNameTag(sf.Tag.Get("cli"))
+		if name == "-" {
+			continue
+		}
+
+		fv := sv.Field(i)
+		if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+			var nested []StructField
+			var err error
+			if sf.Anonymous {
+				nested, err = structFields(fv, namePrefix, envPrefix, keyPrefix)
+			} else {
+				if name == "" {
+					name = kebabCase(sf.Name)
+				}
+				key := sf.Tag.Get("altsrc")
+				if key == "" {
+					key = name
+				}
+				nested, err = structFields(fv, namePrefix+name+"-", envPrefix+sf.Tag.Get("env"), keyPrefix+key+".")
+			}
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = kebabCase(sf.Name)
+		}
+		key := sf.Tag.Get("altsrc")
+		if key == "" {
+			key = name
+		}
+
+		f, err := fieldFlag(sf, fv, namePrefix+name, aliases, envPrefix)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, StructField{Flag: f, Key: keyPrefix + key})
+	}
+	return fields, nil
+}
+
+// parseNameTag splits a cli tag into the name and aliases of the flag
+func parseNameTag(tag string) (name string, aliases []string) {
+	parts := strings.Split(tag, ",")
+	for _, part := range parts[1:] {
+		if alias := strings.TrimPrefix(strings.TrimSpace(part), "alias="); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return strings.TrimSpace(parts[0]), aliases
+}
+
+// kebabCase turns a Go name such as DBHost into db-host
+func kebabCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// fieldFlag returns the flag setting the field fv
+func fieldFlag(sf reflect.StructField, fv reflect.Value, name string, aliases []string, envPrefix string) (Flag, error) {
+	var envVars []string
+	if env := sf.Tag.Get("env"); env != "" {
+		for _, envVar := range strings.Split(env, ",") {
+			envVars = append(envVars, envPrefix+strings.TrimSpace(envVar))
+		}
+	}
+	required, err := parseBoolTag(sf, "required")
+	if err != nil {
+		return nil, err
+	}
+	hidden, err := parseBoolTag(sf, "hidden")
+	if err != nil {
+		return nil, err
+	}
+	usage := sf.Tag.Get("usage")
+	placeholder := sf.Tag.Get("placeholder")
+
+	if choices := sf.Tag.Get("choices"); choices != "" {
+		if fv.Kind() != reflect.String {
+			return nil, fmt.Errorf("choices given for field %s of type %s, which is not a string", sf.Name, sf.Type)
+		}
+		c := Choices{}
+		for _, choice := range strings.Split(choices, ",") {
+			choice = strings.TrimSpace(choice)
+			c[choice] = reflect.ValueOf(choice).Convert(sf.Type).Interface()
+		}
+		return &ChoiceFlag{Name: name, Aliases: aliases, Usage: usage, EnvVars: envVars,
+			Required: required, Hidden: hidden, Placeholder: placeholder,
+			Choice: NewChoice(c), Value: fv.Interface(), Destination: fv.Addr().Interface()}, nil
+	}
+
+	// fields of named types like "type Mode string" are set through a pointer
+	// to their underlying type
+	ptr := fv.Addr()
+	if t, ok := scalarTypes[fv.Kind()]; ok && fv.Type() != durationType {
+		ptr = ptr.Convert(reflect.PtrTo(t))
+	}
+
+	switch p := ptr.Interface().(type) {
+	case *string:
+		return &StringFlag{Name: name, Aliases: aliases, Usage: usage, EnvVars: envVars,
+			Required: required, Hidden: hidden, Placeholder: placeholder, Value: *p, Destination: p}, nil
+	case *bool:
+		return &BoolFlag{Name: name, Aliases: aliases, Usage: usage, EnvVars: envVars,
+			Required: required, Hidden: hidden, Value: *p, Destination: p}, nil
+	case *int:
+		return &IntFlag{Name: name, Aliases: aliases, Usage: usage, EnvVars: envVars,
+			Required: required, Hidden: hidden, Placeholder: placeholder, Value: *p, Destination: p}, nil
+	case *int64:
+		return &Int64Flag{Name: name, Aliases: aliases, Usage: usage, EnvVars: envVars,
+			Required: required, Hidden: hidden, Placeholder: placeholder, Value: *p, Destination: p}, nil
+	case *uint:
+		return &UintFlag{Name: name, Aliases: aliases, Usage: usage, EnvVars: envVars,
+			Required: required, Hidden: hidden, Placeholder: placeholder, Value: *p, Destination: p}, nil
+	case *uint64:
+		return &Uint64Flag{Name: name, Aliases: aliases, Usage: usage, EnvVars: envVars,
+			Required: required, Hidden: hidden, Placeholder: placeholder, Value: *p, Destination: p}, nil
+	case *float64:
+		return &Float64Flag{Name: name, Aliases: aliases, Usage: usage, EnvVars: envVars,
+			Required: required, Hidden: hidden, Placeholder: placeholder, Value: *p, Destination: p}, nil
+	case *time.Duration:
+		return &DurationFlag{Name: name, Aliases: aliases, Usage: usage, EnvVars: envVars,
+			Required: required, Hidden: hidden, Placeholder: placeholder, Value: *p, Destination: p}, nil
+	}
+
+	switch t := sf.Type; {
+	case t.Kind() == reflect.Slice && isScalarType(t.Elem()),
+		t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && isScalarType(t.Elem()):
+		return &collectionFlag{Name: name, Aliases: aliases, Usage: usage, EnvVars: envVars,
+			Required: required, Hidden: hidden, Placeholder: placeholder,
+			field: fv, defaults: copyCollection(fv)}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s of field %s", sf.Type, sf.Name)
+}
+
+func parseBoolTag(sf reflect.StructField, tag string) (bool, error) {
+	value, ok := sf.Tag.Lookup(tag)
+	if !ok {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s tag %q of field %s", tag, value, sf.Name)
+	}
+	return b, nil
+}
+
+// isScalarType reports whether values of type t can be parsed by parseScalar
+func isScalarType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// parseScalar parses s as a value of type t
+func parseScalar(t reflect.Type, s string) (reflect.Value, error) {
+	var value interface{}
+	var err error
+	switch t.Kind() {
+	case reflect.String:
+		value = s
+	case reflect.Bool:
+		value, err = strconv.ParseBool(s)
+	case reflect.Int, reflect.Int64:
+		if t == durationType {
+			value, err = time.ParseDuration(s)
+			break
+		}
+		value, err = strconv.ParseInt(s, 0, t.Bits())
+	case reflect.Uint, reflect.Uint64:
+		value, err = strconv.ParseUint(s, 0, t.Bits())
+	case reflect.Float64:
+		value, err = strconv.ParseFloat(s, 64)
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(value).Convert(t), nil
+}
+
+// copyCollection returns a copy of the slice or map v
+func copyCollection(v reflect.Value) reflect.Value {
+	if v.IsNil() {
+		return reflect.Zero(v.Type())
+	}
+	if v.Kind() == reflect.Slice {
+		return reflect.AppendSlice(reflect.MakeSlice(v.Type(), 0, v.Len()), v)
+	}
+	m := reflect.MakeMapWithSize(v.Type(), v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		m.SetMapIndex(iter.Key(), iter.Value())
+	}
+	return m
+}
+
+// collectionFlag is the flag of a slice or map field bound by FlagsFromStruct
+type collectionFlag struct {
+	Name        string
+	Aliases     []string
+	Usage       string
+	EnvVars     []string
+	Required    bool
+	Hidden      bool
+	Placeholder string
+
+	field    reflect.Value
+	defaults reflect.Value
+}
+
+// IsSet returns whether or not the flag has been set through env
+func (f *collectionFlag) IsSet() bool {
+	return isSetFromEnvOrFile(f.EnvVars, "", true)
+}
+
+// String returns a readable representation of this value
+// (for usage defaults)
+func (f *collectionFlag) String() string {
+	return FlagStringer(f)
+}
+
+// Names returns the names of the flag
+func (f *collectionFlag) Names() []string {
+	return flagNames(f.Name, f.Aliases)
+}
+
+// IsRequired returns whether or not the flag is required
+func (f *collectionFlag) IsRequired() bool {
+	return f.Required
+}
+
+// TakesValue returns true of the flag takes a value, otherwise false
+func (f *collectionFlag) TakesValue() bool {
+	return true
+}
+
+// GetUsage returns the usage string for the flag
+func (f *collectionFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetValue returns the flags value as string representation and an empty
+// string if the flag takes no value at all.
+func (f *collectionFlag) GetValue() string {
+	return strings.Join(f.defaultValues(), ", ")
+}
+
+// IsVisible returns true if the flag is not hidden, otherwise false
+func (f *collectionFlag) IsVisible() bool {
+	return !f.Hidden
+}
+
+// defaultValues returns the formatted default values for help
+func (f *collectionFlag) defaultValues() []string {
+	return formatCollection(f.defaults)
+}
+
+// Apply populates the flag given the flag set and environment
+func (f *collectionFlag) Apply(set *flag.FlagSet) error {
+	f.field.Set(copyCollection(f.defaults))
+	value := &collectionValue{field: f.field}
+
+	if val, ok := flagFromEnvOrFile(f.EnvVars, ""); ok {
+		for _, s := range strings.Split(val, ",") {
+			if err := value.Set(strings.TrimSpace(s)); err != nil {
+				return fmt.Errorf("could not parse %q as values for flag %s: %s", val, f.Name, err)
+			}
+		}
+
+		// Set this to false so that we reset the values if we then set values
+		// from flags that have already been set by the environment.
+		value.hasBeenSet = false
+	}
+
+	for _, name := range f.Names() {
+		set.Var(value, name, f.Usage)
+	}
+
+	return nil
+}
+
+// collectionValue is the flag.Value of a collectionFlag, which sets its field
+type collectionValue struct {
+	field      reflect.Value
+	hasBeenSet bool
+}
+
+// Set parses the value and adds it, replacing the default values first
+func (v *collectionValue) Set(value string) error {
+	t := v.field.Type()
+	if t.Kind() == reflect.Slice {
+		elem, err := parseScalar(t.Elem(), value)
+		if err != nil {
+			return err
+		}
+		if !v.hasBeenSet {
+			v.field.Set(reflect.Zero(t))
+			v.hasBeenSet = true
+		}
+		v.field.Set(reflect.Append(v.field, elem))
+		return nil
+	}
+
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	elem, err := parseScalar(t.Elem(), parts[1])
+	if err != nil {
+		return err
+	}
+	if !v.hasBeenSet || v.field.IsNil() {
+		v.field.Set(reflect.MakeMap(t))
+		v.hasBeenSet = true
+	}
+	v.field.SetMapIndex(reflect.ValueOf(parts[0]).Convert(t.Key()), elem)
+	return nil
+}
+
+func (v *collectionValue) String() string {
+	// the flag package calls String on zero values
+	if !v.field.IsValid() {
+		return ""
+	}
+	return "[" + strings.Join(formatCollection(v.field), ", ") + "]"
+}
+
+func (v *collectionValue) Get() interface{} {
+	return v.field.Interface()
+}
+
+// Accumulates allows the value to fulfill AccumulatingValue
+func (v *collectionValue) Accumulates() bool {
+	return true
+}
+
+// formatCollection formats the elements of a slice, or the entries of a map
+// as key=value sorted by key
+func formatCollection(v reflect.Value) []string {
+	var values []string
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, fmt.Sprint(v.Index(i).Interface()))
+		}
+		return values
+	}
+	iter := v.MapRange()
+	for iter.Next() {
+		values = append(values, fmt.Sprintf("%v=%v", iter.Key().Interface(), iter.Value().Interface()))
+	}
+	sort.Strings(values)
+	return values
+}
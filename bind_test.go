@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindLevel string
+
+type bindDatabase struct {
+	Host    string `usage:"database host" env:"HOST"`
+	Port    int    `usage:"database port" env:"PORT"`
+	Timeout time.Duration
+}
+
+type bindCommon struct {
+	Verbose bool `cli:"verbose,alias=v"`
+}
+
+type bindOptions struct {
+	bindCommon
+	Name     string            `cli:"name,alias=n" env:"BIND_NAME" usage:"the name" required:"true"`
+	Level    bindLevel         `choices:"debug,info,warn" usage:"log level"`
+	Tags     []string          `usage:"tags"`
+	Ports    []uint            `cli:"port"`
+	Labels   map[string]string `usage:"labels"`
+	Limits   map[string]int
+	DB       bindDatabase `cli:"db" env:"BIND_DB_" altsrc:"database"`
+	MaxRetry int64        `altsrc:"retries"`
+	Skipped  string       `cli:"-"`
+	internal string
+}
+
+func TestFlagsFromStruct_Names(t *testing.T) {
+	opts := bindOptions{}
+	fields, err := StructFields(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names, keys []string
+	for _, field := range fields {
+		names = append(names, strings.Join(field.Flag.Names(), ","))
+		keys = append(keys, field.Key)
+	}
+	expect(t, names, []string{"verbose,v", "name,n", "level", "tags", "port", "labels", "limits",
+		"db-host", "db-port", "db-timeout", "max-retry"})
+	expect(t, keys, []string{"verbose", "name", "level", "tags", "port", "labels", "limits",
+		"database.host", "database.port", "database.timeout", "retries"})
+
+	expect(t, fields[1].Flag.(*StringFlag).EnvVars, []string{"BIND_NAME"})
+	expect(t, fields[1].Flag.(*StringFlag).Required, true)
+	expect(t, fields[7].Flag.(*StringFlag).EnvVars, []string{"BIND_DB_HOST"})
+	expect(t, fields[9].Flag.(*DurationFlag).Usage, "")
+}
+
+func TestFlagsFromStruct_Run(t *testing.T) {
+	opts := bindOptions{
+		Level:  "info",
+		Tags:   []string{"default"},
+		Labels: map[string]string{"team": "core"},
+		DB:     bindDatabase{Host: "localhost", Port: 5432},
+	}
+	flags, err := FlagsFromStruct(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got bindOptions
+	app := &App{
+		Flags:  flags,
+		Action: func(*Context) error { got = opts; return nil },
+	}
+
+	err = app.Run([]string{"app", "-v", "-n", "x", "--level", "warn", "--tags", "a", "--tags", "b",
+		"--port", "80", "--port", "443", "--labels", "env=prod", "--limits", "cpu=2",
+		"--db-port", "6543", "--db-timeout", "5s", "--max-retry", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, got, bindOptions{
+		bindCommon: bindCommon{Verbose: true},
+		Name:       "x",
+		Level:      "warn",
+		Tags:       []string{"a", "b"},
+		Ports:      []uint{80, 443},
+		Labels:     map[string]string{"env": "prod"},
+		Limits:     map[string]int{"cpu": 2},
+		DB:         bindDatabase{Host: "localhost", Port: 6543, Timeout: 5 * time.Second},
+		MaxRetry:   3,
+	})
+
+	// the next run starts from the defaults again
+	if err := app.Run([]string{"app", "-n", "y"}); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, got.Level, bindLevel("info"))
+	expect(t, got.Tags, []string{"default"})
+	expect(t, got.Labels, map[string]string{"team": "core"})
+	expect(t, got.DB.Port, 5432)
+}
+
+func TestFlagsFromStruct_NamedTypes(t *testing.T) {
+	type mode string
+	type count int
+	type enabled bool
+
+	opts := struct {
+		Mode    mode
+		Count   count
+		Enabled enabled
+		Modes   []mode
+	}{Mode: "fast", Count: 1}
+	flags, err := FlagsFromStruct(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, flags[0].(*StringFlag).Value, "fast")
+	expect(t, flags[1].(*IntFlag).Value, 1)
+
+	app := &App{Flags: flags, Action: func(*Context) error { return nil }}
+	err = app.Run([]string{"app", "--mode", "slow", "--count", "3", "--enabled", "--modes", "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, opts.Mode, mode("slow"))
+	expect(t, opts.Count, count(3))
+	expect(t, opts.Enabled, enabled(true))
+	expect(t, opts.Modes, []mode{"a"})
+}
+
+func TestFlagsFromStruct_Env(t *testing.T) {
+	defer resetEnv(os.Environ())
+	os.Clearenv()
+	_ = os.Setenv("BIND_NAME", "from-env")
+	_ = os.Setenv("BIND_DB_HOST", "db.example.com")
+
+	opts := bindOptions{}
+	flags, err := FlagsFromStruct(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &App{Flags: flags, Action: func(*Context) error { return nil }}
+	if err := app.Run([]string{"app"}); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, opts.Name, "from-env")
+	expect(t, opts.DB.Host, "db.example.com")
+}
+
+func TestFlagsFromStruct_Errors(t *testing.T) {
+	tests := []struct {
+		v        interface{}
+		expected string
+	}{
+		{bindOptions{}, "expected a pointer to a struct, got cli.bindOptions"},
+		{&struct{ At time.Time }{}, "unsupported type time.Time of field At"},
+		{&struct{ C chan int }{}, "unsupported type chan int of field C"},
+		{&struct {
+			N int `choices:"1,2"`
+		}{}, "choices given for field N of type int, which is not a string"},
+		{&struct {
+			S string `required:"yes"`
+		}{}, `invalid required tag "yes" of field S`},
+	}
+
+	for _, test := range tests {
+		_, err := FlagsFromStruct(test.v)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("expected error %q, got %v", test.expected, err)
+		}
+	}
+}
+
+func TestFlagsFromStruct_Help(t *testing.T) {
+	opts := struct {
+		Tags   []string          `usage:"tags" placeholder:"TAG"`
+		Labels map[string]string `usage:"labels" placeholder:"KEY=VALUE"`
+	}{
+		Tags:   []string{"a", "b"},
+		Labels: map[string]string{"y": "2", "x": "1"},
+	}
+	flags, err := FlagsFromStruct(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, flags[0].String(), "--tags TAG\ttags (default: a, b)\t(accepts multiple inputs)")
+	expect(t, flags[1].String(), "--labels KEY=VALUE\tlabels (default: x=1, y=2)\t(accepts multiple inputs)")
+}
+
+func TestKebabCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Name":     "name",
+		"MaxRetry": "max-retry",
+		"DBHost":   "db-host",
+		"ID":       "id",
+		"UseHTTP2": "use-http2",
+	} {
+		if got := kebabCase(name); got != expected {
+			t.Errorf("kebabCase(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func TestCollectionValue_InvalidEntry(t *testing.T) {
+	labels := map[string]string{}
+	v := &collectionValue{field: reflect.ValueOf(&labels).Elem()}
+	if err := v.Set("novalue"); err == nil {
+		t.Error("expected an error for a map entry without =")
+	}
+}
@@ -19,5 +19,3 @@
 //     app.Run(os.Args)
 //   }
 package cli
-
-//go:generate go run flag-gen/main.go flag-gen/assets_vfsdata.go
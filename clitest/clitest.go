@@ -0,0 +1,213 @@
+// Package clitest runs cli Apps in-process for testing.
+//
+// A run captures what the App writes to its Writer and ErrWriter and the exit
+// code it passes to its OsExiter, without exiting the test binary:
+//
+//	res := clitest.Run(app, "greet", "--name", "Jerry")
+//	if res.Stdout != "Hello Jerry\n" {
+//		t.Errorf("unexpected output %q", res.Stdout)
+//	}
+//
+// The environment and working directory of a run are set with Options. As they
+// are shared by the whole process, runs setting them are serialized with all
+// other runs. Runs of the same App are serialized too, as a run replaces the
+// Writer, ErrWriter, OsExiter and ExitErrHandler of the App; an App under test
+// should not be run concurrently outside of clitest.
+package clitest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Result is the outcome of running an App
+type Result struct {
+	// Stdout is the output written to the Writer of the App
+	Stdout string
+	// Stderr is the output written to the ErrWriter of the App
+	Stderr string
+	// ExitCode is the code passed to OsExiter, or 0 if it was not called
+	ExitCode int
+	// Exited reports whether OsExiter was called
+	Exited bool
+	// Err is the error returned by the App
+	Err error
+	// HandledErr is the error passed to the ExitErrHandler of the App
+	HandledErr error
+}
+
+// Options configure the process state of a run
+type Options struct {
+	// Env sets environment variables for the run. The previous values are
+	// restored afterwards.
+	Env map[string]string
+	// Stdin is the Reader of the App. Defaults to an empty reader.
+	Stdin io.Reader
+	// Dir is the working directory of the run
+	Dir string
+	// Context is passed to App.RunContext. Defaults to context.Background().
+	Context context.Context
+}
+
+// processMu serializes runs changing the environment or working directory,
+// which hold it for writing, with all other runs, which hold it for reading
+var processMu sync.RWMutex
+
+// appLock serializes the runs of an App
+type appLock struct {
+	sync.Mutex
+	// runs counts the runs holding or waiting for the lock
+	runs int
+}
+
+var (
+	appLocksMu sync.Mutex
+	appLocks   = map[*cli.App]*appLock{}
+)
+
+// lockApp locks app for a run and returns the function unlocking it. The lock
+// is dropped once no run holds or waits for it.
+func lockApp(app *cli.App) func() {
+	appLocksMu.Lock()
+	l := appLocks[app]
+	if l == nil {
+		l = &appLock{}
+		appLocks[app] = l
+	}
+	l.runs++
+	appLocksMu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		appLocksMu.Lock()
+		l.runs--
+		if l.runs == 0 {
+			delete(appLocks, app)
+		}
+		appLocksMu.Unlock()
+	}
+}
+
+// Run runs app with the given arguments, which do not include the program
+// name.
+func Run(app *cli.App, args ...string) *Result {
+	return Options{}.Run(app, args...)
+}
+
+// Run runs app with the given arguments, which do not include the program
+// name, after applying the options. The Reader, Writer, ErrWriter, OsExiter
+// and ExitErrHandler of app are replaced for the run only. A custom
+// ExitErrHandler is still called, but should use the Writer, ErrWriter and
+// OsExiter of the App rather than the package level ones to be captured.
+func (o Options) Run(app *cli.App, args ...string) *Result {
+	defer lockApp(app)()
+
+	if len(o.Env) == 0 && o.Dir == "" {
+		processMu.RLock()
+		defer processMu.RUnlock()
+	} else {
+		processMu.Lock()
+		defer processMu.Unlock()
+
+		restoreEnv := setEnv(o.Env)
+		defer restoreEnv()
+
+		if o.Dir != "" {
+			restoreDir, err := chdir(o.Dir)
+			if err != nil {
+				return &Result{Err: err}
+			}
+			defer restoreDir()
+		}
+	}
+
+	ctx := o.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	stdin := o.Stdin
+	if stdin == nil {
+		stdin = strings.NewReader("")
+	}
+
+	// set up first, so that the replaced fields are not taken as defaults
+	app.Setup()
+
+	reader, writer, errWriter := app.Reader, app.Writer, app.ErrWriter
+	osExiter, exitErrHandler := app.OsExiter, app.ExitErrHandler
+	defer func() {
+		app.Reader, app.Writer, app.ErrWriter = reader, writer, errWriter
+		app.OsExiter, app.ExitErrHandler = osExiter, exitErrHandler
+	}()
+
+	var stdout, stderr bytes.Buffer
+	res := &Result{}
+
+	app.Reader = stdin
+	app.Writer = &stdout
+	app.ErrWriter = &stderr
+	app.OsExiter = func(code int) {
+		if !res.Exited {
+			res.ExitCode = code
+			res.Exited = true
+		}
+	}
+	if exitErrHandler != nil {
+		app.ExitErrHandler = func(c *cli.Context, err error) {
+			if res.HandledErr == nil {
+				res.HandledErr = err
+			}
+			exitErrHandler(c, err)
+		}
+	}
+
+	res.Err = app.RunContext(ctx, append([]string{app.Name}, args...))
+	res.Stdout = stdout.String()
+	res.Stderr = stderr.String()
+
+	return res
+}
+
+func setEnv(env map[string]string) func() {
+	type previous struct {
+		value string
+		set   bool
+	}
+
+	saved := map[string]previous{}
+	for key, value := range env {
+		old, ok := os.LookupEnv(key)
+		saved[key] = previous{value: old, set: ok}
+		_ = os.Setenv(key, value)
+	}
+
+	return func() {
+		for key, old := range saved {
+			if old.set {
+				_ = os.Setenv(key, old.value)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		}
+	}
+}
+
+func chdir(dir string) (func(), error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return nil, err
+	}
+
+	return func() { _ = os.Chdir(wd) }, nil
+}
@@ -0,0 +1,282 @@
+package clitest
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+func testApp() *cli.App {
+	return &cli.App{
+		Name:  "greet",
+		Usage: "fight the loneliness!",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "name", Value: "World", EnvVars: []string{"CLITEST_NAME"}},
+		},
+		Action: func(c *cli.Context) error {
+			name := c.String("name")
+			if name == "" {
+				name = "World"
+				if env, ok := os.LookupEnv("CLITEST_NAME"); ok {
+					name = env
+				}
+			}
+			_, _ = fmt.Fprintf(c.App.Writer, "Hello %s\n", name)
+			return nil
+		},
+		Commands: []*cli.Command{
+			{
+				Name:  "echo",
+				Usage: "echo stdin",
+				Action: func(c *cli.Context) error {
+					data, err := ioutil.ReadAll(c.App.Reader)
+					if err != nil {
+						return err
+					}
+					_, _ = c.App.Writer.Write(data)
+					return nil
+				},
+			},
+			{
+				Name:  "pwd",
+				Usage: "print the working directory",
+				Action: func(c *cli.Context) error {
+					wd, err := os.Getwd()
+					if err != nil {
+						return err
+					}
+					_, _ = fmt.Fprintln(c.App.Writer, filepath.Base(wd))
+					return nil
+				},
+			},
+			{
+				Name:  "fail",
+				Usage: "fail with exit code 3",
+				Action: func(*cli.Context) error {
+					return cli.Exit("failed", 3)
+				},
+			},
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	res := Run(testApp(), "--name", "Jerry")
+
+	if res.Err != nil {
+		t.Fatalf("unexpected error: %v", res.Err)
+	}
+	if res.Stdout != "Hello Jerry\n" {
+		t.Errorf("unexpected stdout %q", res.Stdout)
+	}
+	if res.Stderr != "" || res.Exited || res.ExitCode != 0 {
+		t.Errorf("unexpected result %+v", res)
+	}
+}
+
+func TestRun_Exit(t *testing.T) {
+	res := Run(testApp(), "fail")
+
+	if res.Stderr != "failed\n" {
+		t.Errorf("unexpected stderr %q", res.Stderr)
+	}
+	if !res.Exited || res.ExitCode != 3 {
+		t.Errorf("expected exit code 3, got %+v", res)
+	}
+	var exitErr cli.ExitCoder
+	if !errors.As(res.Err, &exitErr) {
+		t.Errorf("expected an ExitCoder, got %v", res.Err)
+	}
+}
+
+func TestRun_UsageError(t *testing.T) {
+	app := testApp()
+	app.ExitCodes = cli.DefaultExitCodes
+
+	res := Run(app, "--nope")
+
+	if !strings.HasPrefix(res.Stdout, "Incorrect Usage. flag provided but not defined: -nope") {
+		t.Errorf("unexpected stdout %q", res.Stdout)
+	}
+	if res.ExitCode != 2 {
+		t.Errorf("expected exit code 2, got %d", res.ExitCode)
+	}
+}
+
+func TestRun_ExitErrHandler(t *testing.T) {
+	app := testApp()
+	handler := func(c *cli.Context, err error) {
+		_, _ = fmt.Fprintln(c.App.ErrWriter, "handled:", err)
+		c.App.OsExiter(4)
+	}
+	app.ExitErrHandler = handler
+
+	res := Run(app, "fail")
+
+	if res.Stderr != "handled: failed\n" || res.ExitCode != 4 {
+		t.Errorf("unexpected result %+v", res)
+	}
+	if res.HandledErr == nil || res.HandledErr.Error() != "failed" {
+		t.Errorf("expected handled error, got %v", res.HandledErr)
+	}
+}
+
+func TestRun_RestoresApp(t *testing.T) {
+	app := testApp()
+	app.Setup()
+	writer, errWriter := app.Writer, app.ErrWriter
+
+	_ = Run(app, "fail")
+	_ = Run(app, "fail")
+
+	if app.Writer != writer || app.ErrWriter != errWriter || app.OsExiter != nil || app.ExitErrHandler != nil {
+		t.Errorf("expected the App to be restored")
+	}
+}
+
+func TestOptions_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clitest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, _ := os.Getwd()
+
+	res := Options{Env: map[string]string{"CLITEST_NAME": "Env"}}.Run(testApp())
+	if res.Stdout != "Hello Env\n" {
+		t.Errorf("unexpected stdout %q", res.Stdout)
+	}
+	if _, ok := os.LookupEnv("CLITEST_NAME"); ok {
+		t.Errorf("expected environment to be restored")
+	}
+
+	res = Options{Stdin: strings.NewReader("piped")}.Run(testApp(), "echo")
+	if res.Stdout != "piped" {
+		t.Errorf("unexpected stdout %q", res.Stdout)
+	}
+
+	res = Options{Dir: dir}.Run(testApp(), "pwd")
+	if res.Stdout != filepath.Base(dir)+"\n" {
+		t.Errorf("unexpected stdout %q", res.Stdout)
+	}
+	if now, _ := os.Getwd(); now != wd {
+		t.Errorf("expected working directory to be restored, got %s", now)
+	}
+
+	res = Options{Dir: filepath.Join(dir, "missing")}.Run(testApp(), "pwd")
+	if res.Err == nil {
+		t.Errorf("expected error for missing directory")
+	}
+}
+
+func TestRun_Concurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			name := fmt.Sprintf("gopher%d", i)
+			res := Run(testApp(), "--name", name)
+			if res.Stdout != "Hello "+name+"\n" {
+				t.Errorf("unexpected stdout %q", res.Stdout)
+			}
+
+			res = Options{Env: map[string]string{"CLITEST_NAME": name}}.Run(testApp())
+			if res.Stdout != "Hello "+name+"\n" {
+				t.Errorf("unexpected stdout %q", res.Stdout)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestRun_SameAppConcurrently(t *testing.T) {
+	app := testApp()
+	app.Setup()
+	writer := app.Writer
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			name := fmt.Sprintf("gopher%d", i)
+			res := Run(app, "--name", name)
+			if res.Stdout != "Hello "+name+"\n" {
+				t.Errorf("unexpected stdout %q", res.Stdout)
+			}
+
+			res = Options{Env: map[string]string{"CLITEST_NAME": name}}.Run(app)
+			if res.Stdout != "Hello "+name+"\n" {
+				t.Errorf("unexpected stdout %q", res.Stdout)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if app.Writer != writer || app.OsExiter != nil {
+		t.Errorf("expected the App to be restored")
+	}
+	if len(appLocks) != 0 {
+		t.Errorf("expected the locks of the App to be dropped, got %d", len(appLocks))
+	}
+}
+
+func TestAssertHelp(t *testing.T) {
+	AssertHelp(t, testApp(), filepath.Join("testdata", "help.golden"))
+	AssertHelp(t, testApp(), filepath.Join("testdata", "help-echo.golden"), "echo")
+}
+
+func TestAssertGolden_Mismatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clitest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "out.golden")
+
+	rec := &recorder{TB: t}
+	AssertGolden(rec, path, "output\n")
+	if !rec.failed || !strings.Contains(rec.message, "-clitest.update") {
+		t.Errorf("expected missing golden file to fail, got %q", rec.message)
+	}
+
+	*Update = true
+	AssertGolden(t, path, "output\n")
+	*Update = false
+
+	AssertGolden(t, path, "output\n")
+
+	rec = &recorder{TB: t}
+	AssertGolden(rec, path, "changed\n")
+	if !rec.failed || !strings.Contains(rec.message, "--- got\nchanged\n") {
+		t.Errorf("expected mismatch to fail, got %q", rec.message)
+	}
+}
+
+// recorder records failures instead of failing the test
+type recorder struct {
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failed = true
+	r.message = fmt.Sprintf(format, args...)
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
@@ -0,0 +1,69 @@
+package clitest
+
+import (
+	"strings"
+)
+
+// diff returns a line based diff turning want into got, with removed lines
+// prefixed by "-", added lines by "+" and common lines by a space. It returns
+// an empty string if want and got are equal.
+func diff(wantName, want, gotName, got string) string {
+	if want == got {
+		return ""
+	}
+
+	a, b := splitLines(want), splitLines(got)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- " + wantName + "\n")
+	sb.WriteString("+++ " + gotName + "\n")
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString(" " + a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString("-" + a[i])
+			i++
+		default:
+			sb.WriteString("+" + b[j])
+			j++
+		}
+	}
+	return sb.String()
+}
+
+// splitLines splits s into lines, each ending with a newline. A missing final
+// newline is marked as in unified diffs.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if last := lines[len(lines)-1]; last == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] = last + "\n\\ No newline at end of file\n"
+	}
+	return lines
+}
@@ -0,0 +1,61 @@
+package clitest
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Update makes the golden file helpers write the actual output to the golden
+// files instead of comparing against them. It is set with the
+// -clitest.update flag of the test binary:
+//
+//	go test ./... -clitest.update
+var Update = flag.Bool("clitest.update", false, "update golden files instead of comparing against them")
+
+// AssertGolden compares got to the contents of the golden file at path and
+// fails the test if they differ. With Update set the file is written instead.
+func AssertGolden(t testing.TB, path string, got string) {
+	t.Helper()
+
+	if *Update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("creating golden file directory: %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file: %v (run with -clitest.update to create it)", err)
+	}
+	// Ignore windows line endings
+	data = bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+
+	if string(data) != got {
+		t.Errorf("output differs from golden file %s (run with -clitest.update to update it)\n--- expected\n%s\n--- got\n%s", path, data, got)
+	}
+}
+
+// Help returns the help output of app for the given command path, or of the
+// App itself if no command is given.
+func Help(app *cli.App, command ...string) string {
+	args := append(command[:len(command):len(command)], "--help")
+	return Run(app, args...).Stdout
+}
+
+// AssertHelp compares the help output of app for the given command path to the
+// golden file at path, as AssertGolden does.
+func AssertHelp(t testing.TB, app *cli.App, path string, command ...string) {
+	t.Helper()
+
+	AssertGolden(t, path, Help(app, command...))
+}
@@ -0,0 +1,447 @@
+package clitest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+// RunScripts runs every script matching the glob pattern against app, each as
+// a subtest named after the script file. See RunScript for the script format.
+func RunScripts(t *testing.T, app *cli.App, pattern string) {
+	t.Helper()
+
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatalf("finding scripts: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no scripts match %s", pattern)
+	}
+
+	for _, file := range files {
+		file := file
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		t.Run(name, func(t *testing.T) {
+			RunScript(t, app, file)
+		})
+	}
+}
+
+// RunScript runs the script at path against app and fails the test if any of
+// its commands fails.
+//
+// A script is a txtar archive. Its comment holds the commands, one per line,
+// and its files are written to a temporary directory, which is the working
+// directory of the script and is available as $WORK. Blank lines and lines
+// starting with # are ignored. Arguments are separated by spaces; text in
+// single quotes is taken literally, with two single quotes standing for one,
+// and $NAME or ${NAME} elsewhere is replaced by the variable set with env. A
+// command prefixed with ! is expected to fail. The commands are:
+//
+//	run [args...]        run the App with the arguments and expect it to
+//	                     succeed, i.e. return no error and exit with code 0
+//	stdout regexp        expect the stdout of the last run to match regexp
+//	stderr regexp        expect the stderr of the last run to match regexp
+//	cmp stdout|stderr f  expect the output of the last run to equal file f
+//	exit code            expect the last run to exit with code
+//	env NAME=value...    set environment variables for the following runs
+//	stdin f              use the contents of file f as stdin of the next run
+//	cd dir               change the working directory of the following runs
+//
+// For example:
+//
+//	run --name Jerry
+//	stdout 'Hello Jerry'
+//	! run --nmae Jerry
+//	cmp stdout usage.txt
+//
+//	-- usage.txt --
+//	Incorrect Usage. flag provided but not defined: -nmae
+//	...
+//
+// With Update set, cmp updates the files in the script with the actual
+// output instead of comparing against them.
+func RunScript(t testing.TB, app *cli.App, path string) {
+	t.Helper()
+
+	if err := runScript(app, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// script is the state of a running script
+type script struct {
+	app     *cli.App
+	path    string
+	archive *archive
+	workdir string
+	dir     string
+	env     map[string]string
+	stdin   *string
+	last    *Result
+	updated bool
+	log     bytes.Buffer
+}
+
+func runScript(app *cli.App, path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	workdir, err := ioutil.TempDir("", "clitest")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workdir)
+
+	s := &script{
+		app:     app,
+		path:    path,
+		archive: parseArchive(data),
+		workdir: workdir,
+		dir:     workdir,
+		env:     map[string]string{"WORK": workdir},
+	}
+	if err := s.extract(); err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(s.archive.comment), "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fmt.Fprintf(&s.log, "> %s\n", line)
+		if err := s.exec(line); err != nil {
+			return fmt.Errorf("%s\n%s:%d: %v", strings.TrimRight(s.log.String(), "\n"), path, i+1, err)
+		}
+	}
+
+	if s.updated {
+		return ioutil.WriteFile(path, s.archive.format(), 0644)
+	}
+	return nil
+}
+
+// extract writes the files of the archive to the work directory
+func (s *script) extract() error {
+	for _, f := range s.archive.files {
+		name := filepath.Join(s.workdir, filepath.FromSlash(f.name))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(name, f.data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exec runs a single command line
+func (s *script) exec(line string) error {
+	args, err := s.parseLine(line)
+	if err != nil {
+		return err
+	}
+
+	neg := false
+	if args[0] == "!" {
+		neg = true
+		args = args[1:]
+		if len(args) == 0 {
+			return fmt.Errorf("missing command after !")
+		}
+	}
+
+	switch cmd := args[0]; cmd {
+	case "run":
+		return s.cmdRun(neg, args[1:])
+	case "stdout", "stderr":
+		return s.cmdMatch(neg, cmd, args[1:])
+	case "cmp":
+		return s.cmdCmp(neg, args[1:])
+	case "exit":
+		return s.cmdExit(neg, args[1:])
+	case "env", "stdin", "cd":
+		if neg {
+			return fmt.Errorf("unsupported: ! %s", cmd)
+		}
+		switch cmd {
+		case "env":
+			return s.cmdEnv(args[1:])
+		case "stdin":
+			return s.cmdStdin(args[1:])
+		default:
+			return s.cmdCd(args[1:])
+		}
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+func (s *script) cmdRun(neg bool, args []string) error {
+	opts := Options{Env: s.env, Dir: s.dir}
+	if s.stdin != nil {
+		opts.Stdin = strings.NewReader(*s.stdin)
+		s.stdin = nil
+	}
+
+	s.last = opts.Run(s.app, args...)
+	if s.last.Stdout != "" {
+		fmt.Fprintf(&s.log, "[stdout]\n%s", fixNewline([]byte(s.last.Stdout)))
+	}
+	if s.last.Stderr != "" {
+		fmt.Fprintf(&s.log, "[stderr]\n%s", fixNewline([]byte(s.last.Stderr)))
+	}
+	if s.last.Exited {
+		fmt.Fprintf(&s.log, "[exit status %d]\n", s.last.ExitCode)
+	}
+
+	failed := s.last.Err != nil || s.last.ExitCode != 0
+	switch {
+	case failed && !neg:
+		if s.last.Err != nil {
+			return fmt.Errorf("unexpected failure: %v", s.last.Err)
+		}
+		return fmt.Errorf("unexpected exit status %d", s.last.ExitCode)
+	case !failed && neg:
+		return fmt.Errorf("unexpected success")
+	}
+	return nil
+}
+
+func (s *script) cmdMatch(neg bool, name string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s regexp", name)
+	}
+	out, err := s.output(name)
+	if err != nil {
+		return err
+	}
+	re, err := regexp.Compile("(?m)" + args[0])
+	if err != nil {
+		return err
+	}
+
+	matched := re.MatchString(out)
+	switch {
+	case !matched && !neg:
+		return fmt.Errorf("no match for `%s` in %s", args[0], name)
+	case matched && neg:
+		return fmt.Errorf("unexpected match for `%s` in %s", args[0], name)
+	}
+	return nil
+}
+
+func (s *script) cmdCmp(neg bool, args []string) error {
+	if neg {
+		return fmt.Errorf("unsupported: ! cmp")
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("usage: cmp stdout|stderr file")
+	}
+	got, err := s.output(args[0])
+	if err != nil {
+		return err
+	}
+
+	name := s.resolve(args[1])
+	if *Update {
+		return s.updateFile(name, got)
+	}
+
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	want := strings.Replace(string(data), "\r\n", "\n", -1)
+	if d := diff(args[1], want, args[0], got); d != "" {
+		return fmt.Errorf("%s and %s differ (run with -clitest.update to update it)\n%s", args[0], args[1], d)
+	}
+	return nil
+}
+
+func (s *script) cmdExit(neg bool, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: exit code")
+	}
+	code, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid exit code %q", args[0])
+	}
+	if s.last == nil {
+		return fmt.Errorf("exit must follow run")
+	}
+
+	equal := s.last.ExitCode == code
+	switch {
+	case !equal && !neg:
+		return fmt.Errorf("exit status %d, expected %d", s.last.ExitCode, code)
+	case equal && neg:
+		return fmt.Errorf("unexpected exit status %d", code)
+	}
+	return nil
+}
+
+func (s *script) cmdEnv(args []string) error {
+	if len(args) == 0 {
+		keys := make([]string, 0, len(s.env))
+		for key := range s.env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&s.log, "%s=%s\n", key, s.env[key])
+		}
+		return nil
+	}
+
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return fmt.Errorf("usage: env NAME=value...")
+		}
+		s.env[arg[:i]] = arg[i+1:]
+	}
+	return nil
+}
+
+func (s *script) cmdStdin(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: stdin file")
+	}
+	data, err := ioutil.ReadFile(s.resolve(args[0]))
+	if err != nil {
+		return err
+	}
+	stdin := string(data)
+	s.stdin = &stdin
+	return nil
+}
+
+func (s *script) cmdCd(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: cd dir")
+	}
+	dir := s.resolve(args[0])
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", args[0])
+	}
+	s.dir = dir
+	return nil
+}
+
+// output returns the stdout or stderr of the last run
+func (s *script) output(name string) (string, error) {
+	if s.last == nil {
+		return "", fmt.Errorf("%s must follow run", name)
+	}
+	switch name {
+	case "stdout":
+		return s.last.Stdout, nil
+	case "stderr":
+		return s.last.Stderr, nil
+	}
+	return "", fmt.Errorf("unknown output %q, expected stdout or stderr", name)
+}
+
+// resolve returns the path of name relative to the working directory
+func (s *script) resolve(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(name))
+}
+
+// updateFile replaces the contents of the archive file at path
+func (s *script) updateFile(path string, data string) error {
+	rel, err := filepath.Rel(s.workdir, path)
+	if err != nil {
+		return err
+	}
+	rel = filepath.ToSlash(rel)
+
+	for i := range s.archive.files {
+		if s.archive.files[i].name == rel {
+			s.archive.files[i].data = []byte(data)
+			s.updated = true
+			return ioutil.WriteFile(path, []byte(data), 0644)
+		}
+	}
+	return fmt.Errorf("cannot update %s: not a file of the script", rel)
+}
+
+// parseLine splits a command line into arguments, expanding variables outside
+// of single quotes
+func (s *script) parseLine(line string) ([]string, error) {
+	var (
+		args   []string
+		arg    strings.Builder
+		inArg  bool
+		quoted bool
+		start  int
+	)
+
+	expand := func(text string) string {
+		return os.Expand(text, func(name string) string { return s.env[name] })
+	}
+
+	for i := 0; ; i++ {
+		if quoted {
+			if i == len(line) {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			if line[i] == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					arg.WriteString(line[start : i+1])
+					i++
+					start = i + 1
+					continue
+				}
+				arg.WriteString(line[start:i])
+				quoted = false
+				start = i + 1
+			}
+			continue
+		}
+
+		if i == len(line) || line[i] == ' ' || line[i] == '\t' || line[i] == '\'' {
+			arg.WriteString(expand(line[start:i]))
+			if i < len(line) && line[i] == '\'' {
+				inArg, quoted = true, true
+				start = i + 1
+				continue
+			}
+			if inArg || i > start {
+				args = append(args, arg.String())
+			}
+			arg.Reset()
+			inArg = false
+			if i == len(line) {
+				break
+			}
+			start = i + 1
+		}
+	}
+
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return args, nil
+}
@@ -0,0 +1,198 @@
+package clitest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunScripts(t *testing.T) {
+	RunScripts(t, testApp(), filepath.Join("testdata", "scripts", "*.txtar"))
+}
+
+func TestRunScript_Failures(t *testing.T) {
+	cases := []struct {
+		name     string
+		script   string
+		expected string
+	}{
+		{
+			name:     "unexpected failure",
+			script:   "run fail\n",
+			expected: "script.txtar:1: unexpected failure: failed",
+		},
+		{
+			name:     "unexpected success",
+			script:   "# comment\n! run\n",
+			expected: "script.txtar:2: unexpected success",
+		},
+		{
+			name:     "no match",
+			script:   "run\nstdout Jerry\n",
+			expected: "script.txtar:2: no match for `Jerry` in stdout",
+		},
+		{
+			name:     "unexpected match",
+			script:   "run\n! stdout World\n",
+			expected: "script.txtar:2: unexpected match for `World` in stdout",
+		},
+		{
+			name:     "exit code",
+			script:   "! run fail\nexit 1\n",
+			expected: "script.txtar:2: exit status 3, expected 1",
+		},
+		{
+			name:     "cmp",
+			script:   "run\ncmp stdout want.txt\n-- want.txt --\nHello Jerry\n",
+			expected: "script.txtar:2: stdout and want.txt differ (run with -clitest.update to update it)\n--- want.txt\n+++ stdout\n-Hello Jerry\n+Hello World\n",
+		},
+		{
+			name:     "output before run",
+			script:   "stdout .\n",
+			expected: "script.txtar:1: stdout must follow run",
+		},
+		{
+			name:     "unknown command",
+			script:   "exec ls\n",
+			expected: `script.txtar:1: unknown command "exec"`,
+		},
+		{
+			name:     "unterminated quote",
+			script:   "stdout 'Hello\n",
+			expected: "script.txtar:1: unterminated quote",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := writeScript(t, c.script)
+			defer os.RemoveAll(filepath.Dir(path))
+
+			err := runScript(testApp(), path)
+			if err == nil {
+				t.Fatalf("expected script to fail")
+			}
+			if !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("expected error to contain %q, got:\n%v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestRunScript_Log(t *testing.T) {
+	path := writeScript(t, "run --name Jerry\n! run fail\nstdout Jerry\n")
+	defer os.RemoveAll(filepath.Dir(path))
+
+	err := runScript(testApp(), path)
+	expected := "> run --name Jerry\n[stdout]\nHello Jerry\n> ! run fail\n[stderr]\nfailed\n[exit status 3]\n> stdout Jerry\n"
+	if err == nil || !strings.HasPrefix(err.Error(), expected) {
+		t.Errorf("expected log %q, got:\n%v", expected, err)
+	}
+}
+
+func TestRunScript_Update(t *testing.T) {
+	path := writeScript(t, "run --name Jerry\ncmp stdout want.txt\n\n-- want.txt --\nHello World\n-- other.txt --\nkept\n")
+	defer os.RemoveAll(filepath.Dir(path))
+
+	*Update = true
+	err := runScript(testApp(), path)
+	*Update = false
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "run --name Jerry\ncmp stdout want.txt\n\n-- want.txt --\nHello Jerry\n-- other.txt --\nkept\n"
+	if string(data) != expected {
+		t.Errorf("expected updated script %q, got %q", expected, data)
+	}
+
+	if err := runScript(testApp(), path); err != nil {
+		t.Errorf("expected updated script to pass: %v", err)
+	}
+}
+
+func TestScript_ParseLine(t *testing.T) {
+	s := &script{env: map[string]string{"NAME": "Jerry"}}
+
+	cases := []struct {
+		line     string
+		expected []string
+	}{
+		{line: "run --name Jerry", expected: []string{"run", "--name", "Jerry"}},
+		{line: "run  \t--name   $NAME", expected: []string{"run", "--name", "Jerry"}},
+		{line: "run --name=${NAME}s", expected: []string{"run", "--name=Jerrys"}},
+		{line: "stdout 'Hello $NAME'", expected: []string{"stdout", "Hello $NAME"}},
+		{line: "stdout 'it''s' ''", expected: []string{"stdout", "it's", ""}},
+		{line: "run a'b c'd", expected: []string{"run", "ab cd"}},
+		{line: "! run $MISSING", expected: []string{"!", "run", ""}},
+	}
+
+	for _, c := range cases {
+		args, err := s.parseLine(c.line)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", c.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(args, c.expected) {
+			t.Errorf("%q: expected %q, got %q", c.line, c.expected, args)
+		}
+	}
+}
+
+func TestArchive(t *testing.T) {
+	data := "comment\n-- a.txt --\nA\n-- dir/b.txt --\nB\n--not a marker--\n-- empty --\n-- c --\nno newline"
+
+	a := parseArchive([]byte(data))
+	expect := []archiveFile{
+		{name: "a.txt", data: []byte("A\n")},
+		{name: "dir/b.txt", data: []byte("B\n--not a marker--\n")},
+		{name: "empty", data: []byte{}},
+		{name: "c", data: []byte("no newline\n")},
+	}
+	if string(a.comment) != "comment\n" {
+		t.Errorf("unexpected comment %q", a.comment)
+	}
+	if len(a.files) != len(expect) {
+		t.Fatalf("expected %d files, got %d", len(expect), len(a.files))
+	}
+	for i, f := range a.files {
+		if f.name != expect[i].name || string(f.data) != string(expect[i].data) {
+			t.Errorf("expected file %q with %q, got %q with %q", expect[i].name, expect[i].data, f.name, f.data)
+		}
+	}
+
+	if formatted := string(a.format()); formatted != data+"\n" {
+		t.Errorf("unexpected format %q", formatted)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if d := diff("want", "a\nb\n", "got", "a\nb\n"); d != "" {
+		t.Errorf("expected no diff, got %q", d)
+	}
+
+	d := diff("want", "a\nb\nc\n", "got", "a\nc\nd")
+	expected := "--- want\n+++ got\n a\n-b\n c\n+d\n\\ No newline at end of file\n"
+	if d != expected {
+		t.Errorf("expected diff %q, got %q", expected, d)
+	}
+}
+
+func writeScript(t *testing.T, script string) string {
+	dir, err := ioutil.TempDir("", "clitest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "script.txtar")
+	if err := ioutil.WriteFile(path, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
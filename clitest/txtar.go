@@ -0,0 +1,85 @@
+package clitest
+
+import (
+	"bytes"
+	"strings"
+)
+
+// archive is a txtar archive: a comment followed by a sequence of files, each
+// introduced by a "-- name --" marker line
+type archive struct {
+	comment []byte
+	files   []archiveFile
+}
+
+type archiveFile struct {
+	name string
+	data []byte
+}
+
+// parseArchive parses the txtar archive in data. Text before the first file
+// marker is the comment.
+func parseArchive(data []byte) *archive {
+	a := &archive{}
+	var name string
+	a.comment, name, data = findFileMarker(data)
+	for name != "" {
+		f := archiveFile{name: name}
+		f.data, name, data = findFileMarker(data)
+		a.files = append(a.files, f)
+	}
+	return a
+}
+
+// format returns the txtar representation of the archive
+func (a *archive) format() []byte {
+	var buf bytes.Buffer
+	buf.Write(fixNewline(a.comment))
+	for _, f := range a.files {
+		buf.WriteString("-- " + f.name + " --\n")
+		buf.Write(fixNewline(f.data))
+	}
+	return buf.Bytes()
+}
+
+// findFileMarker returns the data before the next file marker, the name of the
+// file it introduces and the data after it. The name is empty if there is no
+// further marker.
+func findFileMarker(data []byte) (before []byte, name string, after []byte) {
+	var i int
+	for {
+		if name, after = isFileMarker(data[i:]); name != "" {
+			return data[:i], name, after
+		}
+		j := bytes.IndexByte(data[i:], '\n')
+		if j < 0 {
+			return fixNewline(data), "", nil
+		}
+		i += j + 1
+	}
+}
+
+// isFileMarker reports whether data begins with a file marker line and if so
+// returns the name and the data after the line
+func isFileMarker(data []byte) (name string, after []byte) {
+	if !bytes.HasPrefix(data, []byte("-- ")) {
+		return "", nil
+	}
+	line := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		line, after = data[:i], data[i+1:]
+	}
+	line = bytes.TrimRight(line, "\r")
+	if !bytes.HasSuffix(line, []byte(" --")) || len(line) < len("-- x --") {
+		return "", nil
+	}
+	return strings.TrimSpace(string(line[3 : len(line)-3])), after
+}
+
+// fixNewline terminates non-empty data with a newline
+func fixNewline(data []byte) []byte {
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data[:len(data):len(data)], '\n')
+	}
+	return data
+}
@@ -0,0 +1,282 @@
+// code generator for the typed flag accessors of apps, run by go:generate
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/urfave/cli/v2"
+)
+
+func main() {
+	app := &cli.App{
+		Name:  "flaggen",
+		Usage: "Generates code for flags from a spec file or the Go file defining them",
+		Flags: []cli.Flag{
+			&cli.PathFlag{Name: "spec", Usage: "read the spec from `FILE`"},
+			&cli.PathFlag{Name: "source", Usage: "read the apps and commands from the Go `FILE`"},
+			&cli.PathFlag{Name: "out", Usage: "write the code to `FILE`", Required: true},
+		},
+		Commands: []*cli.Command{
+			{
+				Name:   "accessors",
+				Usage:  "Generates structs holding the values of the flags of commands",
+				Action: AccessorsActionFunc,
+			},
+		},
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// AccessorSpec describes the commands to generate accessors for:
+//
+//	{
+//	  "package": "main",
+//	  "commands": [
+//	    {
+//	      "func": "DeployFlags",
+//	      "flags": [
+//	        {"name": "force", "type": "Bool"},
+//	        {"name": "replicas", "type": "Int", "field": "ReplicaCount"}
+//	      ]
+//	    }
+//	  ]
+//	}
+//
+// which generates a function DeployFlags(c *cli.Context) returning a
+// DeployFlagsValues struct with the fields Force and ReplicaCount. Instead of
+// a spec file, the spec can be read from the Go file defining the commands by
+// sourceSpec.
+type AccessorSpec struct {
+	Package  string
+	Commands []CommandSpec
+}
+
+// CommandSpec describes the flags of a command
+type CommandSpec struct {
+	Func  string
+	Flags []FlagSpec
+}
+
+// FlagSpec describes a flag by its name and the type of cli flag without the
+// Flag suffix, such as "String" for a cli.StringFlag
+type FlagSpec struct {
+	Name  string
+	Type  string
+	Field string
+}
+
+// accessorTypes are the Go types of the values of the flag types, returned by
+// the Context methods named as the flag types
+var accessorTypes = map[string]string{
+	"Bool":          "bool",
+	"ByteSize":      "uint64",
+	"Choice":        "interface{}",
+	"Duration":      "time.Duration",
+	"Float64":       "float64",
+	"Float64Slice":  "[]float64",
+	"Generic":       "interface{}",
+	"HostPort":      "string",
+	"HostPortSlice": "[]string",
+	"IP":            "net.IP",
+	"IPNet":         "*net.IPNet",
+	"IPNetSlice":    "[]*net.IPNet",
+	"IPSlice":       "[]net.IP",
+	"Int":           "int",
+	"Int64":         "int64",
+	"Int64Slice":    "[]int64",
+	"IntSlice":      "[]int",
+	"Path":          "string",
+	"String":        "string",
+	"StringSlice":   "[]string",
+	"Timestamp":     "*time.Time",
+	"Uint":          "uint",
+	"Uint64":        "uint64",
+	"URL":           "*url.URL",
+	"URLSlice":      "[]*url.URL",
+}
+
+// accessorImports are the packages of the qualified Go types of accessorTypes
+var accessorImports = map[string]string{
+	"net.":  "net",
+	"url.":  "net/url",
+	"time.": "time",
+}
+
+var accessorsTemplate = template.Must(template.New("accessors").Parse(`// Code generated by flaggen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+{{if .Imports}}
+{{end -}}
+	"github.com/urfave/cli/v2"
+)
+{{range .Commands}}
+// {{.Func}}Values holds the values of the flags read by {{.Func}}
+type {{.Func}}Values struct {
+{{- range .Flags}}
+	{{.Field}} {{.GoType}}
+{{- end}}
+}
+
+// {{.Func}} returns the values of the flags of the command
+func {{.Func}}(c *cli.Context) {{.Func}}Values {
+	return {{.Func}}Values{
+{{- range .Flags}}
+		{{.Field}}: c.{{.Type}}({{printf "%q" .Name}}),
+{{- end}}
+	}
+}
+{{end}}`))
+
+func AccessorsActionFunc(c *cli.Context) error {
+	var spec AccessorSpec
+	var err error
+	switch {
+	case c.IsSet("spec") == c.IsSet("source"):
+		return fmt.Errorf("either --spec or --source must be given")
+	case c.IsSet("spec"):
+		err = readSpec(c.Path("spec"), &spec)
+	default:
+		spec, err = sourceSpec(c.Path("source"))
+	}
+	if err != nil {
+		return err
+	}
+	code, err := generateAccessors(spec)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.Path("out"), code, 0644)
+}
+
+// generateAccessors returns the formatted code of the accessors of the spec
+func generateAccessors(spec AccessorSpec) ([]byte, error) {
+	if spec.Package == "" {
+		return nil, fmt.Errorf("no package given in spec")
+	}
+
+	type flagData struct {
+		FlagSpec
+		GoType string
+	}
+	type commandData struct {
+		Func  string
+		Flags []flagData
+	}
+	data := struct {
+		Package  string
+		Imports  []string
+		Commands []commandData
+	}{Package: spec.Package}
+	imports := map[string]bool{}
+	funcs := map[string]bool{}
+
+	for _, command := range spec.Commands {
+		if !isExported(command.Func) {
+			return nil, fmt.Errorf("invalid func %q, which must be an exported name", command.Func)
+		}
+		if funcs[command.Func] {
+			return nil, fmt.Errorf("duplicate func %s", command.Func)
+		}
+		funcs[command.Func] = true
+		cd := commandData{Func: command.Func}
+		fields := map[string]bool{}
+		for _, f := range command.Flags {
+			goType, ok := accessorTypes[f.Type]
+			if !ok {
+				return nil, fmt.Errorf("unknown type %q of flag %s of %s", f.Type, f.Name, command.Func)
+			}
+			if f.Field == "" {
+				f.Field = fieldName(f.Name)
+			}
+			if !isExported(f.Field) {
+				return nil, fmt.Errorf("invalid field %q of flag %s of %s", f.Field, f.Name, command.Func)
+			}
+			if fields[f.Field] {
+				return nil, fmt.Errorf("duplicate field %s of %s", f.Field, command.Func)
+			}
+			fields[f.Field] = true
+			for qualifier, path := range accessorImports {
+				if strings.Contains(goType, qualifier) {
+					imports[path] = true
+				}
+			}
+			cd.Flags = append(cd.Flags, flagData{FlagSpec: f, GoType: goType})
+		}
+		data.Commands = append(data.Commands, cd)
+	}
+	for path := range imports {
+		data.Imports = append(data.Imports, path)
+	}
+	sort.Strings(data.Imports)
+
+	return generate(accessorsTemplate, data)
+}
+
+func readSpec(path string, spec interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, spec); err != nil {
+		return fmt.Errorf("invalid spec %s: %v", path, err)
+	}
+	return nil
+}
+
+// generate executes the template and returns the formatted code
+func generate(tmpl *template.Template, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return code, nil
+}
+
+// fieldName turns a flag name such as dry-run into the field name DryRun
+func fieldName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '-' || r == '_' || r == '.' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isExported(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGenerateAccessors(t *testing.T) {
+	code, err := generateAccessors(AccessorSpec{
+		Package: "main",
+		Commands: []CommandSpec{{
+			Func: "DeployFlags",
+			Flags: []FlagSpec{
+				{Name: "force", Type: "Bool"},
+				{Name: "dry-run", Type: "Bool"},
+				{Name: "replicas", Type: "Int", Field: "ReplicaCount"},
+				{Name: "timeout", Type: "Duration"},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `// Code generated by flaggen; DO NOT EDIT.
+
+package main
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// DeployFlagsValues holds the values of the flags read by DeployFlags
+type DeployFlagsValues struct {
+	Force        bool
+	DryRun       bool
+	ReplicaCount int
+	Timeout      time.Duration
+}
+
+// DeployFlags returns the values of the flags of the command
+func DeployFlags(c *cli.Context) DeployFlagsValues {
+	return DeployFlagsValues{
+		Force:        c.Bool("force"),
+		DryRun:       c.Bool("dry-run"),
+		ReplicaCount: c.Int("replicas"),
+		Timeout:      c.Duration("timeout"),
+	}
+}
+`
+	if string(code) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, code)
+	}
+}
+
+func TestGenerateAccessors_Imports(t *testing.T) {
+	code, err := generateAccessors(AccessorSpec{
+		Package: "main",
+		Commands: []CommandSpec{{
+			Func: "ServeFlags",
+			Flags: []FlagSpec{
+				{Name: "listen", Type: "IP"},
+				{Name: "allow", Type: "IPNetSlice"},
+				{Name: "upstream", Type: "URL"},
+				{Name: "name", Type: "String"},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `// Code generated by flaggen; DO NOT EDIT.
+
+package main
+
+import (
+	"net"
+	"net/url"
+
+	"github.com/urfave/cli/v2"
+)
+
+// ServeFlagsValues holds the values of the flags read by ServeFlags
+type ServeFlagsValues struct {
+	Listen   net.IP
+	Allow    []*net.IPNet
+	Upstream *url.URL
+	Name     string
+}
+
+// ServeFlags returns the values of the flags of the command
+func ServeFlags(c *cli.Context) ServeFlagsValues {
+	return ServeFlagsValues{
+		Listen:   c.IP("listen"),
+		Allow:    c.IPNetSlice("allow"),
+		Upstream: c.URL("upstream"),
+		Name:     c.String("name"),
+	}
+}
+`
+	if string(code) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, code)
+	}
+}
+
+func TestGenerateAccessors_Errors(t *testing.T) {
+	tests := []struct {
+		spec     AccessorSpec
+		expected string
+	}{
+		{AccessorSpec{}, "no package given in spec"},
+		{AccessorSpec{Package: "main", Commands: []CommandSpec{{Func: "deploy"}}},
+			`invalid func "deploy", which must be an exported name`},
+		{AccessorSpec{Package: "main", Commands: []CommandSpec{{Func: "Deploy", Flags: []FlagSpec{{Name: "f", Type: "Float"}}}}},
+			`unknown type "Float" of flag f of Deploy`},
+		{AccessorSpec{Package: "main", Commands: []CommandSpec{{Func: "Deploy", Flags: []FlagSpec{
+			{Name: "dry-run", Type: "Bool"}, {Name: "dry_run", Type: "Bool"}}}}},
+			"duplicate field DryRun of Deploy"},
+		{AccessorSpec{Package: "main", Commands: []CommandSpec{{Func: "Deploy"}, {Func: "Deploy"}}},
+			"duplicate func Deploy"},
+	}
+
+	for _, test := range tests {
+		_, err := generateAccessors(test.spec)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("expected error %q, got %v", test.expected, err)
+		}
+	}
+}
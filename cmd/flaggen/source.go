@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// cliImportPath is the import path of the package of the apps and commands
+// read by sourceSpec
+const cliImportPath = "github.com/urfave/cli/v2"
+
+// sourceSpec returns the spec of the flags of the apps and commands defined
+// as composite literals in the Go file at path. The flags of an app are read
+// by a func named after the app, or AppFlags if it has no name, and those of
+// a command by a func named after the command and the commands it is nested
+// in, such as RemoteAddFlags. Commands without flags are left out.
+func sourceSpec(path string) (AccessorSpec, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return AccessorSpec{}, err
+	}
+
+	r := &sourceReader{
+		fset:    fset,
+		visited: map[*ast.CompositeLit]bool{},
+		spec:    AccessorSpec{Package: file.Name.Name},
+	}
+	for _, imp := range file.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p == cliImportPath {
+			r.pkg = "cli"
+			if imp.Name != nil {
+				r.pkg = imp.Name.Name
+			}
+		}
+	}
+	if r.pkg == "" {
+		return AccessorSpec{}, fmt.Errorf("%s does not import %s", path, cliImportPath)
+	}
+
+	// commands nested in others are visited by readCommand first, so that
+	// they take the names of their parents
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if err != nil || !ok || r.visited[lit] {
+			return err == nil
+		}
+		switch r.cliType(lit.Type) {
+		case "App":
+			err = r.readCommand(lit, "", true)
+		case "Command":
+			err = r.readCommand(lit, "", false)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return AccessorSpec{}, err
+	}
+	return r.spec, nil
+}
+
+// sourceReader reads the spec of the flags of apps and commands from a file
+type sourceReader struct {
+	fset    *token.FileSet
+	pkg     string
+	visited map[*ast.CompositeLit]bool
+	spec    AccessorSpec
+}
+
+// cliType returns the name of the type of the cli package expr refers to, or
+// "" if it refers to none
+func (r *sourceReader) cliType(expr ast.Expr) string {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if x, ok := sel.X.(*ast.Ident); !ok || x.Name != r.pkg {
+		return ""
+	}
+	return sel.Sel.Name
+}
+
+// readCommand adds the flags of the app or command lit and its subcommands to
+// the spec, with prefix as the start of their func names
+func (r *sourceReader) readCommand(lit *ast.CompositeLit, prefix string, isApp bool) error {
+	r.visited[lit] = true
+
+	name, err := r.stringField(lit, "Name")
+	if err != nil {
+		return err
+	}
+	if name == "" && !isApp {
+		return r.errorf(lit, "command without a literal name")
+	}
+	funcName := prefix + fieldName(name)
+	if isApp {
+		if name == "" {
+			funcName = "App"
+		}
+		// the commands of an app are named on their own
+		prefix = ""
+	} else {
+		prefix = funcName
+	}
+
+	command := CommandSpec{Func: funcName + "Flags"}
+	for _, elt := range r.sliceField(lit, "Flags") {
+		f, ok := unaddr(elt).(*ast.CompositeLit)
+		if !ok || !strings.HasSuffix(r.cliType(f.Type), "Flag") {
+			return r.errorf(elt, "flag of %s is not a literal of a cli flag type", command.Func)
+		}
+		r.visited[f] = true
+		flagName, err := r.stringField(f, "Name")
+		if err != nil {
+			return err
+		}
+		if flagName == "" {
+			return r.errorf(f, "flag of %s without a literal name", command.Func)
+		}
+		command.Flags = append(command.Flags, FlagSpec{
+			Name: flagName,
+			Type: strings.TrimSuffix(r.cliType(f.Type), "Flag"),
+		})
+	}
+	if len(command.Flags) > 0 {
+		r.spec.Commands = append(r.spec.Commands, command)
+	}
+
+	field := "Subcommands"
+	if isApp {
+		field = "Commands"
+	}
+	for _, elt := range r.sliceField(lit, field) {
+		// commands that are not literals are read where they are defined
+		sub, ok := unaddr(elt).(*ast.CompositeLit)
+		if !ok || sub.Type != nil && r.cliType(sub.Type) != "Command" {
+			continue
+		}
+		if err := r.readCommand(sub, prefix, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stringField returns the value of the field of lit set to a string literal,
+// or "" if the field is not set
+func (r *sourceReader) stringField(lit *ast.CompositeLit, name string) (string, error) {
+	value := fieldValue(lit, name)
+	if value == nil {
+		return "", nil
+	}
+	s, ok := value.(*ast.BasicLit)
+	if !ok || s.Kind != token.STRING {
+		return "", r.errorf(value, "%s is not a string literal", name)
+	}
+	return strconv.Unquote(s.Value)
+}
+
+// sliceField returns the elements of the field of lit set to a slice literal
+func (r *sourceReader) sliceField(lit *ast.CompositeLit, name string) []ast.Expr {
+	if value, ok := fieldValue(lit, name).(*ast.CompositeLit); ok {
+		return value.Elts
+	}
+	return nil
+}
+
+func (r *sourceReader) errorf(node ast.Node, format string, a ...interface{}) error {
+	return fmt.Errorf("%s: %s", r.fset.Position(node.Pos()), fmt.Sprintf(format, a...))
+}
+
+// fieldValue returns the value of the field of lit, or nil if it is not set
+func fieldValue(lit *ast.CompositeLit, name string) ast.Expr {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == name {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
+// unaddr returns the operand of expr if it takes an address
+func unaddr(expr ast.Expr) ast.Expr {
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		return u.X
+	}
+	return expr
+}
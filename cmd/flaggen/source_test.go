@@ -0,0 +1,128 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testSource = `package main
+
+import (
+	"os"
+
+	ucli "github.com/urfave/cli/v2"
+)
+
+var statusCommand = &ucli.Command{
+	Name:  "status",
+	Flags: []ucli.Flag{&ucli.BoolFlag{Name: "short"}},
+}
+
+func main() {
+	app := &ucli.App{
+		Name:  "deploy-tool",
+		Flags: []ucli.Flag{&ucli.StringFlag{Name: "config"}},
+		Commands: []*ucli.Command{
+			{
+				Name: "remote",
+				Subcommands: []*ucli.Command{
+					{
+						Name: "add",
+						Flags: []ucli.Flag{
+							&ucli.URLFlag{Name: "url"},
+							&ucli.DurationFlag{Name: "timeout"},
+						},
+					},
+				},
+			},
+			statusCommand,
+		},
+	}
+	_ = app.Run(os.Args)
+}
+`
+
+func writeSource(t *testing.T, source string) string {
+	dir, err := ioutil.TempDir("", "flaggen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "main.go")
+	if err := ioutil.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSourceSpec(t *testing.T) {
+	path := writeSource(t, testSource)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	spec, err := sourceSpec(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := AccessorSpec{
+		Package: "main",
+		Commands: []CommandSpec{
+			{Func: "StatusFlags", Flags: []FlagSpec{{Name: "short", Type: "Bool"}}},
+			{Func: "DeployToolFlags", Flags: []FlagSpec{{Name: "config", Type: "String"}}},
+			{Func: "RemoteAddFlags", Flags: []FlagSpec{
+				{Name: "url", Type: "URL"},
+				{Name: "timeout", Type: "Duration"},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(spec, expected) {
+		t.Errorf("expected %+v, got %+v", expected, spec)
+	}
+
+	if _, err := generateAccessors(spec); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSourceSpec_Errors(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"package main\n", "does not import github.com/urfave/cli/v2"},
+		{`package main
+
+import "github.com/urfave/cli/v2"
+
+var name = "x"
+
+var c = &cli.Command{Name: name}
+`, "7:28: Name is not a string literal"},
+		{`package main
+
+import "github.com/urfave/cli/v2"
+
+var f = &cli.BoolFlag{Name: "f"}
+
+var c = &cli.Command{Name: "c", Flags: []cli.Flag{f}}
+`, "7:51: flag of CFlags is not a literal of a cli flag type"},
+		{`package main
+
+import "github.com/urfave/cli/v2"
+
+var c = &cli.Command{Flags: []cli.Flag{}}
+`, "5:10: command without a literal name"},
+	}
+
+	for _, test := range tests {
+		path := writeSource(t, test.source)
+		_, err := sourceSpec(path)
+		_ = os.RemoveAll(filepath.Dir(path))
+		if err == nil || !strings.HasSuffix(err.Error(), test.expected) {
+			t.Errorf("expected error ending in %q, got %v", test.expected, err)
+		}
+	}
+}
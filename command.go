@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Command is a subcommand for a cli.App.
@@ -39,6 +41,12 @@ type Command struct {
 	Subcommands []*Command
 	// List of flags to parse
 	Flags []Flag
+	// Loader fills in the Subcommands and Flags of the command once they are
+	// needed, i.e. when the command is run, completed, shown in help, walked
+	// or documented, so that commands of large command trees are only built
+	// if used. Its error is returned instead, e.g. by App.Run. It may run
+	// other Apps, but must not run the App itself.
+	Loader func(*Command) error
 	// Treat all flags as normal arguments if true
 	SkipFlagParsing bool
 	// Boolean to hide built-in help command and help flag
@@ -54,13 +62,28 @@ type Command struct {
 	UseShortOptionHandling bool
 
 	// Full name of command for help, defaults to full command name, including parent commands.
-	HelpName        string
-	commandNamePath []string
+	HelpName string
 
 	// CustomHelpTemplate the text template for the command help topic.
 	// cli.go uses text/template to render templates. You can
 	// render custom help text by setting this variable.
 	CustomHelpTemplate string
+
+	// categories contains the categorized subcommands and is populated when
+	// the command is set up
+	categories CommandCategories
+	// index maps the names of the subcommands to them and is populated when
+	// the command is set up
+	index *commandIndex
+	// loaded is set atomically to 1 once the Loader has been called
+	// successfully
+	loaded uint32
+	// parent is the command this command is a subcommand of and is populated
+	// when the command is set up
+	parent *Command
+	// isRoot is set on the Command running the definitions of an App as the
+	// root of its command tree
+	isRoot bool
 }
 
 type Commands []*Command
@@ -82,121 +105,366 @@ func (c CommandsByName) Swap(i, j int) {
 // FullName returns the full name of the command.
 // For subcommands this ensures that parent commands are part of the command path
 func (c *Command) FullName() string {
-	if c.commandNamePath == nil {
-		return c.Name
+	return strings.Join(c.path(), " ")
+}
+
+// path returns the names of the parent commands of the command and its own
+// name, starting with the command of the App it is beneath
+func (c *Command) path() []string {
+	var path []string
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		path = append([]string{cmd.Name}, path...)
 	}
-	return strings.Join(c.commandNamePath, " ")
+	return path
+}
+
+// Parent returns the command this command is a subcommand of, or nil for the
+// commands of the App. It is set when the App is set up.
+func (c *Command) Parent() *Command {
+	return c.parent
 }
 
 // Run invokes the command given the context, parses ctx.Args() to generate command-specific flags
 func (c *Command) Run(ctx *Context) (err error) {
-	if len(c.Subcommands) > 0 {
-		return c.startApp(ctx)
-	}
+	return c.run(ctx.App, ctx, ctx.Args().Slice())
+}
 
-	if !c.HideHelp && HelpFlag != nil {
-		// append help to flags
-		c.appendFlag(HelpFlag)
+// run runs the command as part of app with the arguments, the first of which
+// is the name the command was invoked with. The root of the command tree of
+// an App runs the same way as the commands beneath it, so that help, shell
+// completion and usage errors are handled alike at every depth.
+func (c *Command) run(app *App, ctx *Context, arguments []string) (err error) {
+	if c.isRoot {
+		if err := c.load(); err != nil {
+			return err
+		}
+	} else if err := c.loadAndSetup(app); err != nil {
+		return err
 	}
 
-	if ctx.App.UseShortOptionHandling {
-		c.UseShortOptionHandling = true
+	set, err := c.newFlagSet()
+	if err != nil {
+		return err
 	}
 
-	set, err := c.parseFlags(ctx.Args(), ctx.shellComplete)
+	var tail []string
+	if len(arguments) > 0 {
+		tail = arguments[1:]
+	}
+	if c.SkipFlagParsing {
+		err = set.Parse(append([]string{"--"}, tail...))
+	} else {
+		err = parseIter(set, c, tail, ctx.shellComplete)
+	}
+	if err == nil {
+		err = normalizeFlags(c.Flags, set, app.AliasPolicy)
+	}
 
-	context := NewContext(ctx.App, set, ctx)
+	context := NewContext(app, set, ctx)
 	context.Command = c
-	if checkCommandCompletions(context, c.Name) {
+
+	if context.shellComplete && c.Command(context.Args().First()) == nil {
+		c.complete(context)
 		return nil
 	}
 
 	if err != nil {
 		if c.OnUsageError != nil {
-			err = c.OnUsageError(context, err, false)
-			context.App.handleExitCoder(context, err)
+			// as before the App ran as a Command, isSubcommand is only set
+			// for commands with subcommands below the root
+			err = c.OnUsageError(context, err, !c.isRoot && len(c.Subcommands) > 0)
+			app.handleExitCoder(context, err)
+			return err
+		}
+		if context.errorFormat == ErrorFormatJSON {
+			app.handleUsageErrorJSON(context, err)
 			return err
 		}
-		_, _ = fmt.Fprintln(context.App.Writer, "Incorrect Usage:", err.Error())
-		_, _ = fmt.Fprintln(context.App.Writer)
-		_ = ShowCommandHelp(context, c.Name)
+		_, _ = fmt.Fprintf(app.Writer, "%s %s\n\n", "Incorrect Usage.", err.Error())
+		_ = c.showHelp(context)
+		app.handleUsageError(context, newUsageError(err))
 		return err
 	}
 
-	if checkCommandHelp(context, c.Name) {
+	if !c.HideHelp && checkHelp(context) {
+		_ = c.showHelp(context)
+		return nil
+	}
+
+	if c.isRoot && !app.HideVersion && checkVersion(context) {
+		ShowVersion(context)
 		return nil
 	}
 
 	cerr := context.checkRequiredFlags(c.Flags)
 	if cerr != nil {
-		_ = ShowCommandHelp(context, c.Name)
+		if context.errorFormat == ErrorFormatJSON {
+			app.handleUsageErrorJSON(context, cerr)
+			return cerr
+		}
+		_ = c.showHelp(context)
+		app.handleUsageError(context, cerr)
 		return cerr
 	}
 
+	// the error of the action is handled together with the error of After,
+	// so that the exit code policy sees both
+	ranAction := false
 	if c.After != nil {
 		defer func() {
 			afterErr := c.After(context)
 			if afterErr != nil {
-				context.App.handleExitCoder(context, err)
 				if err != nil {
 					err = newMultiError(err, afterErr)
 				} else {
 					err = afterErr
 				}
 			}
+			if afterErr != nil || ranAction {
+				app.handleExitCoder(context, err)
+			}
 		}()
 	}
 
 	if c.Before != nil {
-		err = c.Before(context)
-		if err != nil {
-			context.App.handleExitCoder(context, err)
+		if beforeErr := c.Before(context); beforeErr != nil {
+			app.handleExitCoder(context, beforeErr)
+			err = beforeErr
 			return err
 		}
 	}
 
-	if c.Action == nil {
-		c.Action = helpSubcommand.Action
+	if args := context.Args(); args.Present() {
+		if sub := c.Command(args.First()); sub != nil {
+			return sub.run(app, context, args.Slice())
+		}
 	}
 
-	context.Command = c
-	err = c.Action(context)
+	action := c.Action
+	if action == nil {
+		action = helpSubcommand.Action
+	}
+	ranAction = true
+	err = action(context)
 
-	if err != nil {
-		context.App.handleExitCoder(context, err)
+	if c.After == nil {
+		app.handleExitCoder(context, err)
 	}
 	return err
 }
 
-func (c *Command) newFlagSet() (*flag.FlagSet, error) {
-	return flagSet(c.Name, c.Flags)
+// setup prepares the command to be run as part of app. It adds the help flag
+// and, for commands with subcommands, the help command, and indexes and links
+// the subcommands. The changes are reverted by App.Reset.
+func (c *Command) setup(app *App) {
+	if app.UseShortOptionHandling && !c.UseShortOptionHandling {
+		c.UseShortOptionHandling = true
+		app.onReset(func() { c.UseShortOptionHandling = false })
+	}
+
+	if !c.HideHelp {
+		if len(c.Subcommands) > 0 && !c.HideHelpCommand && c.Command(helpCommandName) == nil {
+			// each Command gets its own help command with its own HelpName
+			c.appendCommand(app, newHelpCommand())
+		}
+
+		if helpFlag := app.helpFlag(); helpFlag != nil && !hasFlag(c.Flags, helpFlag) {
+			c.appendFlag(helpFlag)
+			app.onReset(func() { c.Flags = removeFlag(c.Flags, helpFlag) })
+		}
+	}
+
+	c.link(app)
+
+	if c.categories == nil && len(c.Subcommands) > 0 {
+		c.categories = newCommandCategories()
+		for _, sub := range c.Subcommands {
+			c.categories.AddCommand(sub.Category, sub)
+		}
+		sort.Sort(c.categories.(*commandCategories))
+		app.onReset(func() { c.categories = nil })
+	}
+
+	if !c.index.covers(c.Subcommands) {
+		c.index = newCommandIndex(c.Subcommands)
+		app.onReset(func() { c.index = nil })
+	}
 }
 
-func (c *Command) useShortOptionHandling() bool {
-	return c.UseShortOptionHandling
+// link fills in the HelpName and parent of the subcommands, which is all that
+// is done for commands before they are reached
+func (c *Command) link(app *App) {
+	helpName := c.HelpName
+	if helpName == "" {
+		helpName = c.Name
+	}
+
+	for _, sub := range c.Subcommands {
+		sub := sub
+		if sub.HelpName == "" {
+			sub.HelpName = helpName + " " + sub.Name
+			app.onReset(func() { sub.HelpName = "" })
+		}
+		if sub.parent != c {
+			parent := sub.parent
+			sub.parent = c
+			app.onReset(func() { sub.parent = parent })
+		}
+	}
 }
 
-func (c *Command) parseFlags(args Args, shellComplete bool) (*flag.FlagSet, error) {
-	set, err := c.newFlagSet()
-	if err != nil {
-		return nil, err
+// linkCommands links the commands and their subcommands as part of app. The
+// rest of their setup is deferred until they are run, completed, shown in
+// help or walked, so that the commands of large command trees which are not
+// used cost little. Commands which have not been loaded yet are linked once
+// loaded.
+func linkCommands(app *App, commands []*Command) {
+	for _, c := range commands {
+		if c.isLoaded() {
+			c.link(app)
+			linkCommands(app, c.Subcommands)
+		}
 	}
+}
 
-	if c.SkipFlagParsing {
-		return set, set.Parse(append([]string{"--"}, args.Tail()...))
+// setupMu serializes setting up loaded commands while running. Loaders are
+// called outside of it, under the lock of their command.
+var setupMu sync.Mutex
+
+// loadLock serializes the calls of the Loader of a command, so that an App
+// run concurrently loads the command once
+type loadLock struct {
+	sync.Mutex
+	// loads counts the loads holding or waiting for the lock
+	loads int
+}
+
+var (
+	loadLocksMu sync.Mutex
+	loadLocks   = map[*Command]*loadLock{}
+)
+
+// lockLoad locks c for loading and returns the function unlocking it. The
+// lock is dropped once no load holds or waits for it.
+func lockLoad(c *Command) func() {
+	loadLocksMu.Lock()
+	l := loadLocks[c]
+	if l == nil {
+		l = &loadLock{}
+		loadLocks[c] = l
 	}
+	l.loads++
+	loadLocksMu.Unlock()
 
-	err = parseIter(set, c, args.Tail(), shellComplete)
-	if err != nil {
-		return nil, err
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		loadLocksMu.Lock()
+		l.loads--
+		if l.loads == 0 {
+			delete(loadLocks, c)
+		}
+		loadLocksMu.Unlock()
 	}
+}
 
-	err = normalizeFlags(c.Flags, set)
-	if err != nil {
-		return nil, err
+// load calls the Loader of the command unless it has been loaded already
+func (c *Command) load() error {
+	if c.isLoaded() {
+		return nil
+	}
+
+	defer lockLoad(c)()
+	if c.isLoaded() {
+		return nil
+	}
+
+	if err := c.Loader(c); err != nil {
+		return err
+	}
+	atomic.StoreUint32(&c.loaded, 1)
+	return nil
+}
+
+// loadAndSetup loads the command and sets it up to be run as part of app
+func (c *Command) loadAndSetup(app *App) error {
+	wasLoaded := c.isLoaded()
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	setupMu.Lock()
+	if c.Loader != nil && c.index == nil {
+		// the commands beneath a loaded command are linked once it is set up
+		linkCommands(app, c.Subcommands)
 	}
+	c.setup(app)
+	setupMu.Unlock()
 
-	return set, nil
+	if app.Strict && !wasLoaded {
+		return app.validateLoaded(c)
+	}
+	return nil
+}
+
+func (c *Command) isLoaded() bool {
+	return c.Loader == nil || atomic.LoadUint32(&c.loaded) == 1
+}
+
+// loadCommands loads the commands and all commands beneath them
+func loadCommands(commands []*Command) error {
+	for _, c := range commands {
+		if err := c.load(); err != nil {
+			return err
+		}
+		if err := loadCommands(c.Subcommands); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// showHelp prints the help for the command, which is the help of the App for
+// the root command
+func (c *Command) showHelp(ctx *Context) error {
+	if c.isRoot {
+		return ShowAppHelp(ctx)
+	}
+	if err := c.loadAndSetup(ctx.App); err != nil {
+		return err
+	}
+
+	templ := c.CustomHelpTemplate
+	if templ == "" {
+		templ = CommandHelpTemplate
+		if len(c.Subcommands) > 0 {
+			templ = SubcommandHelpTemplate
+		}
+	}
+
+	ctx.App.helpPrinter()(ctx.App.Writer, templ, c)
+	return nil
+}
+
+// complete prints the shell completions for the command
+func (c *Command) complete(ctx *Context) {
+	if err := c.loadAndSetup(ctx.App); err != nil {
+		return
+	}
+	if c.BashComplete != nil {
+		c.BashComplete(ctx)
+		return
+	}
+	DefaultCompleteWithFlags(c)(ctx)
+}
+
+func (c *Command) newFlagSet() (*flag.FlagSet, error) {
+	return flagSet(c.Name, c.Flags)
+}
+
+func (c *Command) useShortOptionHandling() bool {
+	return c.UseShortOptionHandling
 }
 
 // Names returns the names including short names and aliases.
@@ -206,78 +474,97 @@ func (c *Command) Names() []string {
 
 // HasName returns true if Command.Name matches given name
 func (c *Command) HasName(name string) bool {
-	for _, n := range c.Names() {
-		if n == name {
+	if c.Name == name {
+		return true
+	}
+	for _, alias := range c.Aliases {
+		if alias == name {
 			return true
 		}
 	}
 	return false
 }
 
-func (c *Command) startApp(ctx *Context) error {
-	app := &App{
-		Metadata: ctx.App.Metadata,
-		Name:     fmt.Sprintf("%s %s", ctx.App.Name, c.Name),
-	}
+// Command returns the named subcommand. Returns nil if the subcommand does
+// not exist
+func (c *Command) Command(name string) *Command {
+	return c.index.lookup(c.Subcommands, name)
+}
 
-	if c.HelpName == "" {
-		app.HelpName = c.HelpName
-	} else {
-		app.HelpName = app.Name
-	}
-
-	app.Usage = c.Usage
-	app.UsageText = c.UsageText
-	app.Description = c.Description
-	app.ArgsUsage = c.ArgsUsage
-
-	// set CommandNotFound
-	app.CommandNotFound = ctx.App.CommandNotFound
-	app.CustomAppHelpTemplate = c.CustomHelpTemplate
-
-	// set the flags and commands
-	app.Commands = c.Subcommands
-	app.Flags = c.Flags
-	app.HideHelp = c.HideHelp
-	app.HideHelpCommand = c.HideHelpCommand
-
-	app.Version = ctx.App.Version
-	app.HideVersion = true
-	app.Compiled = ctx.App.Compiled
-	app.Reader = ctx.App.Reader
-	app.Writer = ctx.App.Writer
-	app.ErrWriter = ctx.App.ErrWriter
-	app.ExitErrHandler = ctx.App.ExitErrHandler
-	app.UseShortOptionHandling = ctx.App.UseShortOptionHandling
-
-	app.categories = newCommandCategories()
-	for _, command := range c.Subcommands {
-		app.categories.AddCommand(command.Category, command)
+// commandIndex maps the names and aliases of commands to them, so that large
+// numbers of commands are looked up quickly
+type commandIndex struct {
+	// commands which have been indexed, to notice when they change
+	commands []*Command
+	names    map[string]*Command
+}
+
+func newCommandIndex(commands []*Command) *commandIndex {
+	index := &commandIndex{
+		commands: commands,
+		names:    make(map[string]*Command, len(commands)),
 	}
+	for _, c := range commands {
+		for _, name := range append([]string{c.Name}, c.Aliases...) {
+			// the first command with a name wins, as without an index
+			if _, ok := index.names[name]; !ok {
+				index.names[name] = c
+			}
+		}
+	}
+	return index
+}
 
-	sort.Sort(app.categories.(*commandCategories))
+// covers reports whether the index is up to date with the commands
+func (i *commandIndex) covers(commands []*Command) bool {
+	if i == nil || len(i.commands) != len(commands) {
+		return false
+	}
+	return len(commands) == 0 || &i.commands[0] == &commands[0]
+}
 
-	// bash completion
-	app.EnableBashCompletion = ctx.App.EnableBashCompletion
-	if c.BashComplete != nil {
-		app.BashComplete = c.BashComplete
+// lookup returns the named command of the commands, which are searched one
+// by one if the index does not cover them
+func (i *commandIndex) lookup(commands []*Command, name string) *Command {
+	if i.covers(commands) {
+		if c := i.names[name]; c != nil && c.HasName(name) {
+			return c
+		}
+		return nil
 	}
 
-	// set the actions
-	app.Before = c.Before
-	app.After = c.After
-	if c.Action != nil {
-		app.Action = c.Action
-	} else {
-		app.Action = helpSubcommand.Action
+	for _, c := range commands {
+		if c.HasName(name) {
+			return c
+		}
 	}
-	app.OnUsageError = c.OnUsageError
+	return nil
+}
 
-	for index, cc := range app.Commands {
-		app.Commands[index].commandNamePath = []string{c.Name, cc.Name}
+// VisibleCategories returns a slice of categories and subcommands that are
+// Hidden=false
+func (c *Command) VisibleCategories() []CommandCategory {
+	ret := []CommandCategory{}
+	if c.categories == nil {
+		return ret
 	}
+	for _, category := range c.categories.Categories() {
+		if len(category.VisibleCommands()) > 0 {
+			ret = append(ret, category)
+		}
+	}
+	return ret
+}
 
-	return app.RunAsSubcommand(ctx)
+// VisibleCommands returns a slice of the Subcommands with Hidden=false
+func (c *Command) VisibleCommands() []*Command {
+	var ret []*Command
+	for _, command := range c.Subcommands {
+		if !command.Hidden {
+			ret = append(ret, command)
+		}
+	}
+	return ret
 }
 
 // VisibleFlags returns a slice of the Flags with Hidden=false
@@ -287,10 +574,28 @@ func (c *Command) VisibleFlags() []Flag {
 
 func (c *Command) appendFlag(fl Flag) {
 	if !hasFlag(c.Flags, fl) {
-		c.Flags = append(c.Flags, fl)
+		c.Flags = appendFlag(c.Flags, fl)
 	}
 }
 
+func (c *Command) appendCommand(app *App, sub *Command) {
+	if !hasCommand(c.Subcommands, sub) {
+		// never write to the backing array of the Subcommands passed in
+		c.Subcommands = append(c.Subcommands[:len(c.Subcommands):len(c.Subcommands)], sub)
+		app.onReset(func() { c.Subcommands = removeCommand(c.Subcommands, sub) })
+	}
+}
+
+func removeCommand(commands []*Command, command *Command) []*Command {
+	for i, existing := range commands {
+		if existing == command {
+			return append(commands[:i:i], commands[i+1:]...)
+		}
+	}
+
+	return commands
+}
+
 func hasCommand(commands []*Command, command *Command) bool {
 	for _, existing := range commands {
 		if command == existing {
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestCommandFlagParsing(t *testing.T) {
@@ -18,7 +20,7 @@ func TestCommandFlagParsing(t *testing.T) {
 		expectedErr            error
 	}{
 		// Test normal "not ignoring flags" flow
-		{testArgs: []string{"test-cmd", "-break", "blah", "blah"}, skipFlagParsing: false, useShortOptionHandling: false, expectedErr: errors.New("flag provided but not defined: -break")},
+		{testArgs: []string{"test-cmd", "-break", "blah", "blah"}, skipFlagParsing: false, useShortOptionHandling: false, expectedErr: &UnknownFlagError{Name: "break", Arg: "-break"}},
 		{testArgs: []string{"test-cmd", "blah", "blah"}, skipFlagParsing: true, useShortOptionHandling: false, expectedErr: nil},   // Test SkipFlagParsing without any args that look like flags
 		{testArgs: []string{"test-cmd", "blah", "-break"}, skipFlagParsing: true, useShortOptionHandling: false, expectedErr: nil}, // Test SkipFlagParsing with random flag arg
 		{testArgs: []string{"test-cmd", "blah", "-help"}, skipFlagParsing: true, useShortOptionHandling: false, expectedErr: nil},  // Test SkipFlagParsing with "special" help flag arg
@@ -61,17 +63,17 @@ func TestParseAndRunShortOpts(t *testing.T) {
 		{testArgs: args{"foo", "test", "-af"}, expectedErr: nil, expectedArgs: &args{}},
 		{testArgs: args{"foo", "test", "-cf"}, expectedErr: nil, expectedArgs: &args{}},
 		{testArgs: args{"foo", "test", "-acf"}, expectedErr: nil, expectedArgs: &args{}},
-		{testArgs: args{"foo", "test", "--acf"}, expectedErr: errors.New("flag provided but not defined: -acf"), expectedArgs: nil},
-		{testArgs: args{"foo", "test", "-invalid"}, expectedErr: errors.New("flag provided but not defined: -invalid"), expectedArgs: nil},
-		{testArgs: args{"foo", "test", "-acf", "-invalid"}, expectedErr: errors.New("flag provided but not defined: -invalid"), expectedArgs: nil},
-		{testArgs: args{"foo", "test", "--invalid"}, expectedErr: errors.New("flag provided but not defined: -invalid"), expectedArgs: nil},
-		{testArgs: args{"foo", "test", "-acf", "--invalid"}, expectedErr: errors.New("flag provided but not defined: -invalid"), expectedArgs: nil},
+		{testArgs: args{"foo", "test", "--acf"}, expectedErr: &UnknownFlagError{Name: "acf", Arg: "--acf"}, expectedArgs: nil},
+		{testArgs: args{"foo", "test", "-invalid"}, expectedErr: &UnknownFlagError{Name: "invalid", Arg: "-invalid"}, expectedArgs: nil},
+		{testArgs: args{"foo", "test", "-acf", "-invalid"}, expectedErr: &UnknownFlagError{Name: "invalid", Arg: "-invalid"}, expectedArgs: nil},
+		{testArgs: args{"foo", "test", "--invalid"}, expectedErr: &UnknownFlagError{Name: "invalid", Arg: "--invalid"}, expectedArgs: nil},
+		{testArgs: args{"foo", "test", "-acf", "--invalid"}, expectedErr: &UnknownFlagError{Name: "invalid", Arg: "--invalid"}, expectedArgs: nil},
 		{testArgs: args{"foo", "test", "-acf", "arg1", "-invalid"}, expectedErr: nil, expectedArgs: &args{"arg1", "-invalid"}},
 		{testArgs: args{"foo", "test", "-acf", "arg1", "--invalid"}, expectedErr: nil, expectedArgs: &args{"arg1", "--invalid"}},
 		{testArgs: args{"foo", "test", "-acfi", "not-arg", "arg1", "-invalid"}, expectedErr: nil, expectedArgs: &args{"arg1", "-invalid"}},
 		{testArgs: args{"foo", "test", "-i", "ivalue"}, expectedErr: nil, expectedArgs: &args{}},
 		{testArgs: args{"foo", "test", "-i", "ivalue", "arg1"}, expectedErr: nil, expectedArgs: &args{"arg1"}},
-		{testArgs: args{"foo", "test", "-i"}, expectedErr: errors.New("flag needs an argument: -i"), expectedArgs: nil},
+		{testArgs: args{"foo", "test", "-i"}, expectedErr: &MissingValueError{Name: "i", Arg: "-i"}, expectedArgs: nil},
 	}
 
 	for _, c := range cases {
@@ -388,7 +390,7 @@ func TestCommand_NoVersionFlagOnCommands(t *testing.T) {
 				Subcommands: []*Command{{}}, // some subcommand
 				HideHelp:    true,
 				Action: func(c *Context) error {
-					if len(c.App.VisibleFlags()) != 0 {
+					if len(c.Command.VisibleFlags()) != 0 {
 						t.Fatal("unexpected flag on command")
 					}
 					return nil
@@ -422,3 +424,214 @@ func TestCommand_CanAddVFlagOnCommands(t *testing.T) {
 	err := app.Run([]string{"foo", "bar"})
 	expect(t, err, nil)
 }
+
+func TestCommand_FullName(t *testing.T) {
+	leaf := &Command{Name: "leaf"}
+	middle := &Command{Name: "middle", Subcommands: []*Command{leaf}}
+	top := &Command{Name: "top", Subcommands: []*Command{middle}}
+	app := &App{Name: "app", Commands: []*Command{top}}
+
+	expect(t, leaf.FullName(), "leaf")
+
+	app.Setup()
+	expect(t, top.FullName(), "top")
+	expect(t, middle.FullName(), "top middle")
+	expect(t, leaf.FullName(), "top middle leaf")
+	expect(t, leaf.HelpName, "app top middle leaf")
+
+	expect(t, top.Parent(), (*Command)(nil))
+	expect(t, middle.Parent(), top)
+	expect(t, leaf.Parent(), middle)
+
+	app.Reset()
+	expect(t, leaf.Parent(), (*Command)(nil))
+	expect(t, leaf.FullName(), "leaf")
+}
+
+// newLazyApp returns an App with a "lazy" command built by a Loader, which
+// counts its calls in loads and stores the level the "leaf" command sees
+func newLazyApp(loads *int, level *string) *App {
+	return &App{
+		Name:   "app",
+		Writer: ioutil.Discard,
+		Commands: []*Command{
+			{Name: "eager", Usage: "built upfront", Action: func(*Context) error { return nil }},
+			{
+				Name:  "lazy",
+				Usage: "built when needed",
+				Loader: func(c *Command) error {
+					*loads++
+					c.Flags = []Flag{&StringFlag{Name: "level"}}
+					c.Subcommands = []*Command{{
+						Name:  "leaf",
+						Usage: "the end",
+						Action: func(ctx *Context) error {
+							*level = ctx.String("level")
+							return nil
+						},
+					}}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func TestCommand_Loader(t *testing.T) {
+	var loads int
+	var level string
+	app := newLazyApp(&loads, &level)
+
+	app.Setup()
+	expect(t, loads, 0)
+	expect(t, app.Run([]string{"app", "eager"}), nil)
+	expect(t, loads, 0)
+
+	expect(t, app.Run([]string{"app", "lazy", "--level", "high", "leaf"}), nil)
+	expect(t, loads, 1)
+	expect(t, level, "high")
+	expect(t, app.Command("lazy").Command("leaf").FullName(), "lazy leaf")
+
+	expect(t, app.Run([]string{"app", "lazy", "--level", "low", "leaf"}), nil)
+	expect(t, loads, 1)
+	expect(t, level, "low")
+}
+
+func TestCommand_Loader_Help(t *testing.T) {
+	var loads int
+	var level string
+	app := newLazyApp(&loads, &level)
+	output := &bytes.Buffer{}
+	app.Writer = output
+
+	expect(t, app.Run([]string{"app", "--help"}), nil)
+	expect(t, loads, 0)
+	expect(t, strings.Contains(output.String(), "built when needed"), true)
+
+	output.Reset()
+	expect(t, app.Run([]string{"app", "help", "lazy"}), nil)
+	expect(t, loads, 1)
+	expect(t, strings.Contains(output.String(), "--level"), true)
+	expect(t, strings.Contains(output.String(), "leaf"), true)
+	expect(t, strings.Contains(output.String(), "--help"), true)
+}
+
+func TestCommand_Loader_Walk(t *testing.T) {
+	var loads int
+	var level string
+	app := newLazyApp(&loads, &level)
+
+	leaf := app.FindCommand("lazy", "leaf")
+	expect(t, loads, 1)
+	expect(t, leaf != nil, true)
+	expect(t, leaf.FullName(), "lazy leaf")
+
+	var paths []string
+	expect(t, app.Walk(func(path []string, c *Command) error {
+		paths = append(paths, strings.Join(path, " "))
+		return nil
+	}), nil)
+	expect(t, loads, 1)
+	expect(t, paths, []string{"eager", "lazy", "lazy leaf", "lazy help", "help"})
+
+	app = newLazyApp(&loads, &level)
+	md, err := app.ToMarkdown()
+	expect(t, err, nil)
+	expect(t, loads, 2)
+	expect(t, strings.Contains(md, "--level"), true)
+}
+
+func TestCommand_Loader_Error(t *testing.T) {
+	loaderErr := errors.New("cannot build")
+	calls := 0
+	app := &App{
+		Writer: ioutil.Discard,
+		Commands: []*Command{{
+			Name: "broken",
+			Loader: func(*Command) error {
+				calls++
+				return loaderErr
+			},
+		}},
+	}
+
+	expect(t, app.Run([]string{"app", "broken"}), loaderErr)
+	expect(t, app.FindCommand("broken"), (*Command)(nil))
+	_, err := app.ToMarkdown()
+	expect(t, err, loaderErr)
+	expect(t, app.Walk(func([]string, *Command) error { return nil }), loaderErr)
+
+	// failed loads are retried
+	expect(t, calls, 4)
+}
+
+func TestCommand_Loader_RunsOtherApp(t *testing.T) {
+	var innerLoads int
+	var level string
+	inner := newLazyApp(&innerLoads, &level)
+
+	outer := &App{
+		Writer: ioutil.Discard,
+		Commands: []*Command{{
+			Name: "outer",
+			Loader: func(c *Command) error {
+				// loading a command does not block loading and running others
+				return inner.Run([]string{"inner", "lazy", "--level", "high", "leaf"})
+			},
+			Action: func(*Context) error { return nil },
+		}},
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- outer.Run([]string{"outer", "outer"}) }()
+	select {
+	case err := <-done:
+		expect(t, err, nil)
+	case <-time.After(5 * time.Second):
+		t.Fatal("loading the command deadlocked")
+	}
+	expect(t, innerLoads, 1)
+	expect(t, level, "high")
+}
+
+func TestCommand_Loader_Concurrent(t *testing.T) {
+	var loads int
+	var level string
+	app := newLazyApp(&loads, &level)
+	app.Setup()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := app.Run([]string{"app", "lazy", "--help"}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	expect(t, loads, 1)
+}
+
+func TestCommand_SetupDeferred(t *testing.T) {
+	leaf := &Command{Name: "leaf"}
+	middle := &Command{Name: "middle", Subcommands: []*Command{leaf}}
+	top := &Command{Name: "top", Subcommands: []*Command{middle}}
+	other := &Command{Name: "other", Subcommands: []*Command{{Name: "unused"}}}
+	app := &App{Name: "app", Writer: ioutil.Discard, Commands: []*Command{top, other}}
+
+	app.Setup()
+	expect(t, leaf.FullName(), "top middle leaf")
+	expect(t, len(top.Flags), 0)
+	expect(t, top.Command("help"), (*Command)(nil))
+
+	expect(t, app.Run([]string{"app", "top", "middle", "leaf"}), nil)
+	expect(t, len(top.Flags), 1)
+	expect(t, top.Command("help") != nil, true)
+	expect(t, len(leaf.Flags), 1)
+
+	// commands which are not run are not set up
+	expect(t, len(other.Flags), 0)
+	expect(t, other.Command("help"), (*Command)(nil))
+}
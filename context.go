@@ -15,6 +15,7 @@ type Context struct {
 	App           *App
 	Command       *Command
 	shellComplete bool
+	errorFormat   ErrorFormat
 	flagSet       *flag.FlagSet
 	parentContext *Context
 }
@@ -25,6 +26,7 @@ func NewContext(app *App, set *flag.FlagSet, parentCtx *Context) *Context {
 	if parentCtx != nil {
 		c.Context = parentCtx.Context
 		c.shellComplete = parentCtx.shellComplete
+		c.errorFormat = parentCtx.errorFormat
 		if parentCtx.flagSet == nil {
 			parentCtx.flagSet = &flag.FlagSet{}
 		}
@@ -39,6 +41,29 @@ func NewContext(app *App, set *flag.FlagSet, parentCtx *Context) *Context {
 	return c
 }
 
+// commandScope returns the closest command in the lineage of the context which
+// has subcommands, or nil if there is none. Commands given by name, e.g. to
+// the help command, are looked up among its subcommands.
+func (c *Context) commandScope() *Command {
+	for ctx := c; ctx != nil; ctx = ctx.parentContext {
+		if ctx.Command != nil && (ctx.Command.isRoot || len(ctx.Command.Subcommands) > 0) {
+			return ctx.Command
+		}
+	}
+	return nil
+}
+
+// lookupCommand returns the named command in the scope of the context
+func (c *Context) lookupCommand(name string) *Command {
+	if scope := c.commandScope(); scope != nil {
+		return scope.Command(name)
+	}
+	if c.App != nil {
+		return c.App.Command(name)
+	}
+	return nil
+}
+
 // NumFlags returns the number of flags set
 func (c *Context) NumFlags() int {
 	return c.flagSet.NFlag()
@@ -186,7 +211,7 @@ func (context *Context) checkRequiredFlags(flags []Flag) requiredFlagsErr {
 	}
 
 	if len(missingFlags) != 0 {
-		return &errRequiredFlags{missingFlags: missingFlags}
+		return &RequiredFlagsError{MissingFlags: missingFlags}
 	}
 
 	return nil
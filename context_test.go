@@ -232,6 +232,38 @@ func TestContext_IsSet_fromEnv(t *testing.T) {
 	expect(t, uIsSet, false)
 }
 
+func TestContext_IsSet_fromCommandLine(t *testing.T) {
+	var nameIsSet, nIsSet, countIsSet, ranSub bool
+
+	name := &StringFlag{Name: "name", Aliases: []string{"n"}}
+	count := &IntFlag{Name: "count"}
+	a := App{
+		Flags: []Flag{name, count},
+		Commands: []*Command{
+			{
+				Name: "sub",
+				Action: func(ctx *Context) error {
+					nameIsSet = ctx.IsSet("name")
+					nIsSet = ctx.IsSet("n")
+					countIsSet = ctx.IsSet("count")
+					ranSub = true
+					return nil
+				},
+			},
+		},
+	}
+
+	expect(t, a.Run([]string{"run", "-n", "frob", "sub"}), nil)
+	expect(t, ranSub, true)
+	expect(t, nameIsSet, true)
+	expect(t, nIsSet, true)
+	expect(t, countIsSet, false)
+
+	// parsing records the flag in the run's context, not on its definition
+	expect(t, name.IsSet(), false)
+	expect(t, count.IsSet(), false)
+}
+
 func TestContext_NumFlags(t *testing.T) {
 	set := flag.NewFlagSet("test", 0)
 	set.Bool("myflag", false, "doc")
@@ -48,6 +48,10 @@ type cliTemplate struct {
 }
 
 func (a *App) writeDocTemplate(w io.Writer, sectionNum int) error {
+	if err := loadCommands(a.Commands); err != nil {
+		return err
+	}
+
 	const name = "cli"
 	t, err := template.New(name).Parse(MarkdownDocTemplate)
 	if err != nil {
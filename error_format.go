@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ErrorFormat selects how errors are reported on ErrWriter
+type ErrorFormat string
+
+const (
+	// ErrorFormatText reports errors as plain text, and usage errors along with
+	// the help text. This is the default.
+	ErrorFormatText ErrorFormat = "text"
+	// ErrorFormatJSON reports each error as a single JSON object with the
+	// fields "message", "exit_code", "type", "flag", "flags", "command" and
+	// "suggestions", omitting empty ones.
+	ErrorFormatJSON ErrorFormat = "json"
+)
+
+// ErrorFormatFlag selects the ErrorFormat for a single run. It is added to an
+// App if its ErrorFormat is set, unless the App sets its own ErrorFormatFlag.
+// Unknown formats are ignored.
+var ErrorFormatFlag Flag = &StringFlag{
+	Name:  "error-format",
+	Usage: "Format of error messages, either \"text\" or \"json\"",
+}
+
+// errorReport is the JSON representation of an error
+type errorReport struct {
+	Message     string   `json:"message"`
+	ExitCode    int      `json:"exit_code,omitempty"`
+	Type        string   `json:"type"`
+	Flag        string   `json:"flag,omitempty"`
+	Flags       []string `json:"flags,omitempty"`
+	Command     string   `json:"command,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+func newErrorReport(context *Context, err error, exitCode int) *errorReport {
+	report := &errorReport{
+		Message:  err.Error(),
+		ExitCode: exitCode,
+		Type:     "error",
+	}
+
+	var (
+		unknownFlag     *UnknownFlagError
+		missingValue    *MissingValueError
+		invalidValue    *InvalidValueError
+		flagConflict    *FlagConflictError
+		requiredFlags   *RequiredFlagsError
+		unknownCommand  *UnknownCommandError
+		multiErr        MultiError
+		isMultiErr      = errors.As(err, &multiErr)
+		isUnknownFlag   = errors.As(err, &unknownFlag)
+		isUnknownCmd    = errors.As(err, &unknownCommand)
+		isRequiredFlags = errors.As(err, &requiredFlags)
+	)
+
+	switch {
+	case isMultiErr:
+		report.Type = "multiple"
+	case isUnknownFlag:
+		report.Type = "unknown_flag"
+		report.Flag = unknownFlag.Name
+		report.Suggestions = suggestFlags(unknownFlag.Name, contextFlags(context))
+	case errors.As(err, &missingValue):
+		report.Type = "missing_value"
+		report.Flag = missingValue.Name
+	case errors.As(err, &invalidValue):
+		report.Type = "invalid_value"
+		report.Flag = invalidValue.Name
+	case errors.As(err, &flagConflict):
+		report.Type = "flag_conflict"
+		report.Flag = flagConflict.Name
+		report.Flags = []string{flagConflict.Name, flagConflict.Other}
+	case isRequiredFlags:
+		report.Type = "required_flags"
+		report.Flags = requiredFlags.MissingFlags
+	case isUnknownCmd:
+		report.Type = "unknown_command"
+		report.Command = unknownCommand.Name
+		if context != nil && context.App != nil {
+			report.Suggestions = suggestCommands(unknownCommand.Name, context.App.VisibleCommands())
+		}
+	case errors.Is(err, ErrUsage):
+		report.Type = "usage"
+	}
+
+	return report
+}
+
+func writeErrorReport(w io.Writer, report *errorReport) {
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// handleExitCoderJSON is the JSON counterpart of HandleExitCoder
+func (a *App) handleExitCoderJSON(context *Context, err error) {
+	if err == nil {
+		return
+	}
+
+	if exitErr, ok := err.(ExitCoder); ok {
+		writeErrorReport(a.errWriter(), newErrorReport(context, err, exitErr.ExitCode()))
+		a.osExiter()(exitErr.ExitCode())
+		return
+	}
+
+	if multiErr, ok := err.(MultiError); ok {
+		code := exitCodeByPolicy(collectExitCodes(multiErr), a.ExitCodePolicy)
+		writeErrorReport(a.errWriter(), newErrorReport(context, err, code))
+		a.osExiter()(code)
+		return
+	}
+}
+
+// handleUsageErrorJSON reports a usage error as JSON in place of the text
+// message and help output and then hands it on to the error handling
+func (a *App) handleUsageErrorJSON(context *Context, err error) {
+	err = a.ExitCodes.apply(newUsageError(err))
+	exitErr, isExitCoder := err.(ExitCoder)
+
+	if a.ExitErrHandler != nil {
+		code := 0
+		if isExitCoder {
+			code = exitErr.ExitCode()
+		}
+		writeErrorReport(a.errWriter(), newErrorReport(context, err, code))
+		a.ExitErrHandler(context, err)
+		return
+	}
+
+	if isExitCoder {
+		a.handleExitCoderJSON(context, err)
+		return
+	}
+	writeErrorReport(a.errWriter(), newErrorReport(context, err, 0))
+}
+
+// checkErrorFormatFlag returns the ErrorFormat selected by the arguments, or
+// the ErrorFormat of the App if none is selected. Like the shell completion
+// flag it is checked before parsing so that it applies to parsing errors too.
+func checkErrorFormatFlag(a *App, arguments []string) ErrorFormat {
+	if a.ErrorFormat == "" {
+		return ErrorFormatText
+	}
+
+	errorFormatFlag := a.errorFormatFlag()
+	if errorFormatFlag == nil {
+		return a.ErrorFormat
+	}
+
+	format := a.ErrorFormat
+	for i := 1; i < len(arguments); i++ {
+		arg := arguments[i]
+		if arg == "--" {
+			break
+		}
+		for _, name := range errorFormatFlag.Names() {
+			var value string
+			for _, prefix := range []string{"-", "--"} {
+				if arg == prefix+name && i+1 < len(arguments) {
+					value = arguments[i+1]
+				} else if strings.HasPrefix(arg, prefix+name+"=") {
+					value = strings.TrimPrefix(arg, prefix+name+"=")
+				}
+			}
+			switch ErrorFormat(value) {
+			case ErrorFormatText, ErrorFormatJSON:
+				format = ErrorFormat(value)
+			}
+		}
+	}
+	return format
+}
+
+// contextFlags returns the flags defined for the command of the context
+func contextFlags(context *Context) []Flag {
+	if context == nil {
+		return nil
+	}
+	if context.Command != nil && context.Command.Name != "" {
+		return context.Command.Flags
+	}
+	if context.App != nil {
+		return context.App.Flags
+	}
+	return nil
+}
+
+const maxSuggestions = 3
+
+func suggestFlags(name string, flags []Flag) []string {
+	var candidates []string
+	for _, f := range flags {
+		if vf, ok := f.(VisibleFlag); ok && !vf.IsVisible() {
+			continue
+		}
+		for _, n := range f.Names() {
+			candidates = append(candidates, n)
+		}
+	}
+
+	suggestions := suggest(name, candidates)
+	for i, s := range suggestions {
+		suggestions[i] = prefixFor(s) + s
+	}
+	return suggestions
+}
+
+func suggestCommands(name string, commands []*Command) []string {
+	var candidates []string
+	for _, c := range commands {
+		candidates = append(candidates, c.Names()...)
+	}
+	return suggest(name, candidates)
+}
+
+// suggest returns the candidates which are most similar to input, ordered by
+// their edit distance
+func suggest(input string, candidates []string) []string {
+	type match struct {
+		name     string
+		distance int
+	}
+
+	maxDistance := len(input) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	var matches []match
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		if c == "" || seen[c] {
+			continue
+		}
+		seen[c] = true
+
+		d := editDistance(input, c)
+		if d <= maxDistance || (len(input) > 1 && strings.HasPrefix(c, input)) {
+			matches = append(matches, match{name: c, distance: d})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var ret []string
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		ret = append(ret, matches[i].name)
+	}
+	return ret
+}
+
+// editDistance returns the Levenshtein distance of a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestApp_ErrorFormatJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     []string
+		expected errorReport
+	}{
+		{
+			name: "unknown flag",
+			args: []string{"app", "--error-format", "json", "--nmae"},
+			expected: errorReport{
+				Message:     "flag provided but not defined: -nmae",
+				ExitCode:    2,
+				Type:        "unknown_flag",
+				Flag:        "nmae",
+				Suggestions: []string{"--name"},
+			},
+		},
+		{
+			name: "missing value in command",
+			args: []string{"app", "--error-format=json", "cmd", "--count"},
+			expected: errorReport{
+				Message:  "flag needs an argument: -count",
+				ExitCode: 2,
+				Type:     "missing_value",
+				Flag:     "count",
+			},
+		},
+		{
+			name: "invalid value",
+			args: []string{"app", "-error-format", "json", "cmd", "--count", "many"},
+			expected: errorReport{
+				Message:  `invalid value "many" for flag -count: parse error`,
+				ExitCode: 2,
+				Type:     "invalid_value",
+				Flag:     "count",
+			},
+		},
+		{
+			name: "missing required flag",
+			args: []string{"app", "--error-format", "json", "req"},
+			expected: errorReport{
+				Message:  `Required flag "lang" not set`,
+				ExitCode: 2,
+				Type:     "required_flags",
+				Flags:    []string{"lang"},
+			},
+		},
+		{
+			name: "unknown help topic",
+			args: []string{"app", "--error-format", "json", "help", "cdm"},
+			expected: errorReport{
+				Message:     "No help topic for 'cdm'",
+				ExitCode:    3,
+				Type:        "unknown_command",
+				Command:     "cdm",
+				Suggestions: []string{"cmd"},
+			},
+		},
+		{
+			name: "action error",
+			args: []string{"app", "--error-format", "json", "fail"},
+			expected: errorReport{
+				Message:  "failed",
+				ExitCode: 5,
+				Type:     "error",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			code := -1
+			OsExiter = func(rc int) { code = rc }
+			defer func() { OsExiter = fakeOsExiter }()
+
+			errWriter := &bytes.Buffer{}
+			ErrWriter = errWriter
+			defer func() { ErrWriter = fakeErrWriter }()
+
+			output := &bytes.Buffer{}
+			app := &App{
+				Writer:      output,
+				ErrorFormat: ErrorFormatText,
+				ExitCodes:   DefaultExitCodes,
+				Flags:       []Flag{&StringFlag{Name: "name"}},
+				Commands: []*Command{
+					{
+						Name:   "cmd",
+						Flags:  []Flag{&IntFlag{Name: "count"}},
+						Action: func(*Context) error { return nil },
+					},
+					{
+						Name:   "req",
+						Flags:  []Flag{&StringFlag{Name: "lang", Required: true}},
+						Action: func(*Context) error { return nil },
+					},
+					{
+						Name:   "fail",
+						Action: func(*Context) error { return Exit("failed", 5) },
+					},
+				},
+			}
+
+			_ = app.Run(c.args)
+
+			var report errorReport
+			if err := json.Unmarshal(errWriter.Bytes(), &report); err != nil {
+				t.Fatalf("expected a JSON error report, got %q: %v", errWriter.String(), err)
+			}
+			expect(t, report, c.expected)
+			expect(t, code, c.expected.ExitCode)
+			expect(t, output.String(), "")
+		})
+	}
+}
+
+func TestApp_ErrorFormatText(t *testing.T) {
+	errWriter := &bytes.Buffer{}
+	ErrWriter = errWriter
+	defer func() { ErrWriter = fakeErrWriter }()
+
+	output := &bytes.Buffer{}
+	app := &App{
+		Writer:      output,
+		ErrorFormat: ErrorFormatJSON,
+		Flags:       []Flag{&StringFlag{Name: "name"}},
+	}
+
+	_ = app.Run([]string{"app", "--error-format", "text", "--nmae"})
+
+	expect(t, errWriter.String(), "")
+	if !strings.HasPrefix(output.String(), "Incorrect Usage. flag provided but not defined: -nmae") {
+		t.Errorf("expected text usage error, got:\n%s", output.String())
+	}
+}
+
+func TestApp_ErrorFormatJSON_ExitErrHandler(t *testing.T) {
+	errWriter := &bytes.Buffer{}
+	ErrWriter = errWriter
+	defer func() { ErrWriter = fakeErrWriter }()
+
+	var handled error
+	app := &App{
+		Writer:      ioutil.Discard,
+		ErrorFormat: ErrorFormatJSON,
+		ExitErrHandler: func(_ *Context, err error) {
+			handled = err
+		},
+	}
+
+	err := app.Run([]string{"app", "--nope"})
+
+	var unknownFlag *UnknownFlagError
+	expect(t, errors.As(err, &unknownFlag), true)
+	expect(t, errors.As(handled, &unknownFlag), true)
+	if !strings.Contains(errWriter.String(), `"type":"unknown_flag"`) {
+		t.Errorf("expected JSON error report, got %q", errWriter.String())
+	}
+}
+
+func TestApp_ErrorFormatFlag(t *testing.T) {
+	app := &App{}
+	app.Setup()
+	expect(t, app.Flags, []Flag{HelpFlag})
+
+	app = &App{ErrorFormat: ErrorFormatText}
+	app.Setup()
+	expect(t, app.Flags, []Flag{HelpFlag, ErrorFormatFlag})
+
+	formatFlag := &StringFlag{Name: "output-errors"}
+	app = &App{ErrorFormat: ErrorFormatText, ErrorFormatFlag: formatFlag}
+	app.Setup()
+	expect(t, app.Flags, []Flag{HelpFlag, formatFlag})
+	expect(t, checkErrorFormatFlag(app, []string{"app", "--output-errors", "json"}), ErrorFormatJSON)
+	expect(t, checkErrorFormatFlag(app, []string{"app", "--error-format", "json"}), ErrorFormatText)
+}
+
+func TestCheckErrorFormatFlag(t *testing.T) {
+	cases := []struct {
+		format   ErrorFormat
+		args     []string
+		expected ErrorFormat
+	}{
+		{format: "", args: []string{"app", "--error-format", "json"}, expected: ErrorFormatText},
+		{format: ErrorFormatJSON, args: []string{"app"}, expected: ErrorFormatJSON},
+		{format: ErrorFormatText, args: []string{"app", "--error-format", "json"}, expected: ErrorFormatJSON},
+		{format: ErrorFormatText, args: []string{"app", "cmd", "-error-format=json"}, expected: ErrorFormatJSON},
+		{format: ErrorFormatText, args: []string{"app", "--error-format", "yaml"}, expected: ErrorFormatText},
+		{format: ErrorFormatText, args: []string{"app", "--", "--error-format", "json"}, expected: ErrorFormatText},
+		{format: ErrorFormatText, args: []string{"app", "--error-format"}, expected: ErrorFormatText},
+	}
+
+	for _, c := range cases {
+		app := &App{ErrorFormat: c.format}
+		expect(t, checkErrorFormatFlag(app, c.args), c.expected)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"name", "names", "number", "verbose", "n"}
+
+	expect(t, suggest("nmae", candidates), []string{"name"})
+	expect(t, suggest("nam", candidates), []string{"name", "n", "names"})
+	expect(t, suggest("verbos", candidates), []string{"verbose"})
+	expect(t, suggest("xyzzy", candidates), []string(nil))
+}
+
+func TestEditDistance(t *testing.T) {
+	expect(t, editDistance("", ""), 0)
+	expect(t, editDistance("kitten", "sitting"), 3)
+	expect(t, editDistance("nmae", "name"), 2)
+	expect(t, editDistance("abc", ""), 3)
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -41,10 +42,36 @@ func (m *multiError) Error() string {
 // Errors returns a copy of the errors slice
 func (m *multiError) Errors() []error {
 	errs := make([]error, len(*m))
+	copy(errs, *m)
+	return errs
+}
+
+// Unwrap returns the wrapped errors so that errors.Is and errors.As inspect
+// each of them
+func (m *multiError) Unwrap() []error {
+	return m.Errors()
+}
+
+// Is reports whether any of the wrapped errors matches target, for the
+// versions of Go whose errors.Is does not follow Unwrap() []error
+func (m *multiError) Is(target error) bool {
 	for _, err := range *m {
-		errs = append(errs, err)
+		if errors.Is(err, target) {
+			return true
+		}
 	}
-	return errs
+	return false
+}
+
+// As finds the first of the wrapped errors that matches target, for the
+// versions of Go whose errors.As does not follow Unwrap() []error
+func (m *multiError) As(target interface{}) bool {
+	for _, err := range *m {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
 }
 
 type requiredFlagsErr interface {
@@ -52,21 +79,161 @@ type requiredFlagsErr interface {
 	getMissingFlags() []string
 }
 
-type errRequiredFlags struct {
-	missingFlags []string
+// RequiredFlagsError is returned if required flags have not been set
+type RequiredFlagsError struct {
+	// Names of the missing flags
+	MissingFlags []string
 }
 
-func (e *errRequiredFlags) Error() string {
-	numberOfMissingFlags := len(e.missingFlags)
+func (e *RequiredFlagsError) Error() string {
+	numberOfMissingFlags := len(e.MissingFlags)
 	if numberOfMissingFlags == 1 {
-		return fmt.Sprintf("Required flag %q not set", e.missingFlags[0])
+		return fmt.Sprintf("Required flag %q not set", e.MissingFlags[0])
 	}
-	joinedMissingFlags := strings.Join(e.missingFlags, ", ")
+	joinedMissingFlags := strings.Join(e.MissingFlags, ", ")
 	return fmt.Sprintf("Required flags %q not set", joinedMissingFlags)
 }
 
-func (e *errRequiredFlags) getMissingFlags() []string {
-	return e.missingFlags
+func (e *RequiredFlagsError) getMissingFlags() []string {
+	return e.MissingFlags
+}
+
+// Is reports whether target is ErrRequiredFlags or ErrUsage
+func (e *RequiredFlagsError) Is(target error) bool {
+	return target == ErrRequiredFlags || target == ErrUsage
+}
+
+// UnknownFlagError is returned if a flag is used which has not been defined
+type UnknownFlagError struct {
+	// Name of the flag without leading dashes
+	Name string
+	// The command line argument containing the flag
+	Arg string
+}
+
+func (e *UnknownFlagError) Error() string {
+	return fmt.Sprintf("flag provided but not defined: -%s", e.Name)
+}
+
+// Is reports whether target is ErrUsage
+func (e *UnknownFlagError) Is(target error) bool {
+	return target == ErrUsage
+}
+
+// MissingValueError is returned if a flag taking a value is used without one
+type MissingValueError struct {
+	// Name of the flag without leading dashes
+	Name string
+	// The command line argument containing the flag
+	Arg string
+}
+
+func (e *MissingValueError) Error() string {
+	return fmt.Sprintf("flag needs an argument: -%s", e.Name)
+}
+
+// Is reports whether target is ErrUsage
+func (e *MissingValueError) Is(target error) bool {
+	return target == ErrUsage
+}
+
+// InvalidValueError is returned if the value given for a flag cannot be parsed
+type InvalidValueError struct {
+	// Name of the flag without leading dashes
+	Name string
+	// The value as given on the command line
+	Value string
+	// The error returned when parsing the value
+	Err error
+
+	message string
+}
+
+func (e *InvalidValueError) Error() string {
+	if e.message != "" {
+		return e.message
+	}
+	return fmt.Sprintf("invalid value %q for flag -%s: %v", e.Value, e.Name, e.Err)
+}
+
+// Is reports whether target is ErrUsage
+func (e *InvalidValueError) Is(target error) bool {
+	return target == ErrUsage
+}
+
+// Unwrap returns the error returned when parsing the value
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// FlagConflictError is returned if several names of the same flag are used
+type FlagConflictError struct {
+	// Name of the flag as used
+	Name string
+	// The other name of the same flag which has been used as well
+	Other string
+}
+
+func (e *FlagConflictError) Error() string {
+	return "Cannot use two forms of the same flag: " + e.Name + " " + e.Other
+}
+
+// Is reports whether target is ErrUsage
+func (e *FlagConflictError) Is(target error) bool {
+	return target == ErrUsage
+}
+
+// UnknownCommandError is returned if a command or help topic cannot be found
+type UnknownCommandError struct {
+	// Name of the command as given on the command line
+	Name string
+}
+
+func (e *UnknownCommandError) Error() string {
+	return fmt.Sprintf("No help topic for '%v'", e.Name)
+}
+
+// Is reports whether target is ErrCommandNotFound
+func (e *UnknownCommandError) Is(target error) bool {
+	return target == ErrCommandNotFound
+}
+
+// DefinitionError is returned by App.Validate for a mistake in the definition
+// of an App or one of its commands
+type DefinitionError struct {
+	// Full name of the command, empty for the App itself
+	Command string
+	// Description of the mistake
+	Message string
+}
+
+func (e *DefinitionError) Error() string {
+	if e.Command == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("command %q: %s", e.Command, e.Message)
+}
+
+// classifiedError attaches one of the sentinel errors to an error without
+// changing its message
+type classifiedError struct {
+	error
+	class error
+}
+
+func newUsageError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{error: err, class: ErrUsage}
+}
+
+func (e *classifiedError) Is(target error) bool {
+	return target == e.class
+}
+
+func (e *classifiedError) Unwrap() error {
+	return e.error
 }
 
 // ErrorFormatter is the interface that will suitably format the error output
@@ -115,15 +282,41 @@ func (ee *exitError) ExitCode() int {
 	return ee.exitCode
 }
 
+// Unwrap returns the message if it is an error
+func (ee *exitError) Unwrap() error {
+	if err, ok := ee.message.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// ExitCodePolicy selects the exit code for a MultiError wrapping several
+// errors implementing ExitCoder.
+type ExitCodePolicy int
+
+const (
+	// ExitCodeLast uses the exit code of the last ExitCoder. This is the default.
+	ExitCodeLast ExitCodePolicy = iota
+	// ExitCodeFirst uses the exit code of the first ExitCoder
+	ExitCodeFirst
+	// ExitCodeHighest uses the highest exit code of all ExitCoders
+	ExitCodeHighest
+)
+
 // HandleExitCoder handles errors implementing ExitCoder by printing their
 // message and calling OsExiter with the given exit code.
 //
-// If the given error instead implements MultiError, each error will be checked
-// for the ExitCoder interface, and OsExiter will be called with the last exit
-// code found, or exit code 1 if no ExitCoder is found.
+// If the given error instead implements MultiError, each error will be printed
+// and checked for the ExitCoder interface, nested MultiErrors included.
+// OsExiter will be called with the last exit code found, or exit code 1 if no
+// ExitCoder is found. Use App.ExitCodePolicy to choose a different exit code.
 //
 // This function is the default error-handling behavior for an App.
 func HandleExitCoder(err error) {
+	handleExitCoder(err, ExitCodeLast, ErrWriter, OsExiter)
+}
+
+func handleExitCoder(err error, policy ExitCodePolicy, errWriter io.Writer, osExiter func(int)) {
 	if err == nil {
 		return
 	}
@@ -131,33 +324,76 @@ func HandleExitCoder(err error) {
 	if exitErr, ok := err.(ExitCoder); ok {
 		if err.Error() != "" {
 			if _, ok := exitErr.(ErrorFormatter); ok {
-				_, _ = fmt.Fprintf(ErrWriter, "%+v\n", err)
+				_, _ = fmt.Fprintf(errWriter, "%+v\n", err)
 			} else {
-				_, _ = fmt.Fprintln(ErrWriter, err)
+				_, _ = fmt.Fprintln(errWriter, err)
 			}
 		}
-		OsExiter(exitErr.ExitCode())
+		osExiter(exitErr.ExitCode())
 		return
 	}
 
 	if multiErr, ok := err.(MultiError); ok {
-		code := handleMultiError(multiErr)
-		OsExiter(code)
+		code := handleMultiError(multiErr, policy, errWriter)
+		osExiter(code)
 		return
 	}
 }
 
-func handleMultiError(multiErr MultiError) int {
-	code := 1
+func handleMultiError(multiErr MultiError, policy ExitCodePolicy, errWriter io.Writer) int {
+	var codes []int
+	printMultiError(multiErr, errWriter, &codes)
+	return exitCodeByPolicy(codes, policy)
+}
+
+// exitCodeByPolicy selects one of the collected exit codes, or 1 if there are
+// none
+func exitCodeByPolicy(codes []int, policy ExitCodePolicy) int {
+	if len(codes) == 0 {
+		return 1
+	}
+
+	switch policy {
+	case ExitCodeFirst:
+		return codes[0]
+	case ExitCodeHighest:
+		code := codes[0]
+		for _, c := range codes[1:] {
+			if c > code {
+				code = c
+			}
+		}
+		return code
+	default:
+		return codes[len(codes)-1]
+	}
+}
+
+// printMultiError prints all errors wrapped by multiErr and collects the exit
+// codes of those implementing ExitCoder in order
+func printMultiError(multiErr MultiError, errWriter io.Writer, codes *[]int) {
 	for _, merr := range multiErr.Errors() {
 		if multiErr2, ok := merr.(MultiError); ok {
-			code = handleMultiError(multiErr2)
+			printMultiError(multiErr2, errWriter, codes)
 		} else if merr != nil {
-			fmt.Fprintln(ErrWriter, merr)
+			fmt.Fprintln(errWriter, merr)
 			if exitErr, ok := merr.(ExitCoder); ok {
-				code = exitErr.ExitCode()
+				*codes = append(*codes, exitErr.ExitCode())
 			}
 		}
 	}
-	return code
+}
+
+// collectExitCodes collects the exit codes of all errors wrapped by multiErr
+// implementing ExitCoder in order
+func collectExitCodes(multiErr MultiError) []int {
+	var codes []int
+	for _, merr := range multiErr.Errors() {
+		if multiErr2, ok := merr.(MultiError); ok {
+			codes = append(codes, collectExitCodes(multiErr2)...)
+		} else if exitErr, ok := merr.(ExitCoder); ok {
+			codes = append(codes, exitErr.ExitCode())
+		}
+	}
+	return codes
 }
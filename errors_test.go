@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"strconv"
 	"testing"
 )
 
@@ -140,3 +142,257 @@ func TestHandleExitCoder_MultiErrorWithFormat(t *testing.T) {
 	expect(t, called, true)
 	expect(t, ErrWriter.(*bytes.Buffer).String(), "This the format: err1\nThis the format: err2\n")
 }
+
+func TestApp_Run_UsageErrors(t *testing.T) {
+	newApp := func() *App {
+		return &App{
+			Writer: ioutil.Discard,
+			Flags: []Flag{
+				&IntFlag{Name: "count", Aliases: []string{"c"}},
+				&BoolFlag{Name: "force"},
+			},
+			Commands: []*Command{
+				{
+					Name:  "sub",
+					Flags: []Flag{&StringFlag{Name: "name", Required: true}},
+					Subcommands: []*Command{
+						{Name: "leaf", Action: func(*Context) error { return nil }},
+					},
+				},
+			},
+			Action: func(*Context) error { return nil },
+		}
+	}
+
+	t.Run("unknown flag", func(t *testing.T) {
+		err := newApp().Run([]string{"app", "--nope=1"})
+
+		var target *UnknownFlagError
+		expect(t, errors.As(err, &target), true)
+		expect(t, target, &UnknownFlagError{Name: "nope", Arg: "--nope=1"})
+		expect(t, errors.Is(err, ErrUsage), true)
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		err := newApp().Run([]string{"app", "--count"})
+
+		var target *MissingValueError
+		expect(t, errors.As(err, &target), true)
+		expect(t, target, &MissingValueError{Name: "count", Arg: "--count"})
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		err := newApp().Run([]string{"app", "-c", "many"})
+
+		var target *InvalidValueError
+		expect(t, errors.As(err, &target), true)
+		expect(t, target.Name, "c")
+		expect(t, target.Value, "many")
+		expect(t, target.Err.Error(), "parse error")
+		expect(t, err.Error(), `invalid value "many" for flag -c: parse error`)
+	})
+
+	t.Run("invalid boolean value", func(t *testing.T) {
+		err := newApp().Run([]string{"app", "--force=maybe"})
+
+		var target *InvalidValueError
+		expect(t, errors.As(err, &target), true)
+		expect(t, target.Name, "force")
+		expect(t, target.Value, "maybe")
+	})
+
+	t.Run("flag conflict", func(t *testing.T) {
+		err := newApp().Run([]string{"app", "-c", "1", "--count", "2"})
+
+		var target *FlagConflictError
+		expect(t, errors.As(err, &target), true)
+		expect(t, errors.Is(err, ErrUsage), true)
+	})
+
+	t.Run("required flags", func(t *testing.T) {
+		err := newApp().Run([]string{"app", "sub", "leaf"})
+
+		var target *RequiredFlagsError
+		expect(t, errors.As(err, &target), true)
+		expect(t, target.MissingFlags, []string{"name"})
+		expect(t, errors.Is(err, ErrRequiredFlags), true)
+		expect(t, errors.Is(err, ErrUsage), true)
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		err := newApp().Run([]string{"app", "sub", "--name", "x", "nope"})
+
+		var target *UnknownCommandError
+		expect(t, errors.As(err, &target), true)
+		expect(t, target.Name, "nope")
+		expect(t, errors.Is(err, ErrCommandNotFound), true)
+	})
+
+	t.Run("OnUsageError", func(t *testing.T) {
+		var target *InvalidValueError
+		app := newApp()
+		app.OnUsageError = func(_ *Context, err error, _ bool) error {
+			if !errors.As(err, &target) {
+				t.Errorf("expected an InvalidValueError, got %T", err)
+			}
+			return nil
+		}
+
+		_ = app.Run([]string{"app", "--count", "many"})
+		expect(t, target.Name, "count")
+	})
+}
+
+// int8Value is a Generic value which returns the errors of strconv
+type int8Value int8
+
+func (v *int8Value) Set(s string) error {
+	n, err := strconv.ParseInt(s, 10, 8)
+	*v = int8Value(n)
+	return err
+}
+
+func (v *int8Value) String() string {
+	return strconv.Itoa(int(*v))
+}
+
+func TestApp_Run_InvalidValueKeepsSetError(t *testing.T) {
+	app := &App{
+		Writer: ioutil.Discard,
+		Flags: []Flag{
+			&GenericFlag{Name: "level", Aliases: []string{"l"}, Value: new(int8Value)},
+			&BoolFlag{Name: "force"},
+		},
+		Action: func(*Context) error { return nil },
+	}
+
+	err := app.Run([]string{"app", "-l", "300"})
+	var target *InvalidValueError
+	expect(t, errors.As(err, &target), true)
+	expect(t, target.Name, "l")
+	expect(t, target.Value, "300")
+	expect(t, errors.Is(err, strconv.ErrRange), true)
+	expect(t, errors.Is(err, ErrUsage), true)
+	expect(t, err.Error(), `invalid value "300" for flag -l: strconv.ParseInt: parsing "300": value out of range`)
+
+	err = app.Run([]string{"app", "--force=maybe"})
+	expect(t, errors.As(err, &target), true)
+	expect(t, target.Name, "force")
+	expect(t, target.Value, "maybe")
+	expect(t, target.Err.Error(), "parse error")
+}
+
+func TestMultiError_Errors(t *testing.T) {
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+	multiErr := newMultiError(err1, err2)
+
+	errs := multiErr.Errors()
+	expect(t, errs, []error{err1, err2})
+
+	errs[0] = nil
+	expect(t, multiErr.Errors(), []error{err1, err2})
+}
+
+func TestMultiError_IsAs(t *testing.T) {
+	errAction := errors.New("action failed")
+	exitErr := Exit("after failed", 7)
+	err := newMultiError(fmt.Errorf("wrapped: %w", errAction), newMultiError(exitErr))
+
+	expect(t, errors.Is(err, errAction), true)
+	expect(t, errors.Is(err, exitErr), true)
+
+	var exitCoder ExitCoder
+	expect(t, errors.As(err, &exitCoder), true)
+	expect(t, exitCoder.ExitCode(), 7)
+}
+
+func TestHandleExitCoder_MultiErrorPolicy(t *testing.T) {
+	cases := []struct {
+		policy   ExitCodePolicy
+		expected int
+	}{
+		{policy: ExitCodeLast, expected: 4},
+		{policy: ExitCodeFirst, expected: 3},
+		{policy: ExitCodeHighest, expected: 9},
+	}
+
+	for _, c := range cases {
+		exitCode := 0
+		osExiter := func(rc int) {
+			exitCode = rc
+		}
+		errWriter := &bytes.Buffer{}
+
+		err := newMultiError(
+			Exit("first", 3),
+			errors.New("no code"),
+			newMultiError(Exit("highest", 9), nil),
+			Exit("last", 4),
+		)
+		handleExitCoder(err, c.policy, errWriter, osExiter)
+
+		expect(t, exitCode, c.expected)
+		expect(t, errWriter.String(), "first\nno code\nhighest\nlast\n")
+	}
+}
+
+func TestApp_ExitCodePolicy(t *testing.T) {
+	exitCode := 0
+	OsExiter = func(rc int) {
+		exitCode = rc
+	}
+	ErrWriter = &bytes.Buffer{}
+
+	defer func() {
+		OsExiter = fakeOsExiter
+		ErrWriter = fakeErrWriter
+	}()
+
+	errAction := errors.New("action failed")
+	app := &App{
+		ExitCodePolicy: ExitCodeHighest,
+		Action: func(*Context) error {
+			return errAction
+		},
+		After: func(*Context) error {
+			return Exit("after failed", 5)
+		},
+	}
+
+	err := app.Run([]string{"app"})
+
+	expect(t, errors.Is(err, errAction), true)
+	var exitCoder ExitCoder
+	expect(t, errors.As(err, &exitCoder), true)
+	expect(t, exitCode, 5)
+}
+
+func TestApp_ExitCodePolicyActionAndAfterExitCoders(t *testing.T) {
+	for policy, want := range map[ExitCodePolicy]int{
+		ExitCodeLast:    5,
+		ExitCodeFirst:   3,
+		ExitCodeHighest: 5,
+	} {
+		var exitCodes []int
+		app := &App{
+			ExitCodePolicy: policy,
+			Writer:         ioutil.Discard,
+			ErrWriter:      ioutil.Discard,
+			Action: func(*Context) error {
+				return Exit("action failed", 3)
+			},
+			After: func(*Context) error {
+				return Exit("after failed", 5)
+			},
+		}
+		OsExiter = func(rc int) {
+			exitCodes = append(exitCodes, rc)
+		}
+
+		_ = app.Run([]string{"app"})
+
+		expect(t, exitCodes, []int{want})
+	}
+	OsExiter = fakeOsExiter
+}
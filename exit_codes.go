@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+var (
+	// ErrUsage is matched by all errors caused by incorrect usage of an App,
+	// such as unknown flags or invalid flag values.
+	ErrUsage = errors.New("incorrect usage")
+	// ErrRequiredFlags is matched by errors reporting required flags that
+	// have not been set. Such errors also match ErrUsage.
+	ErrRequiredFlags = errors.New("required flags not set")
+	// ErrCommandNotFound is matched by errors reporting an unknown command or
+	// help topic.
+	ErrCommandNotFound = errors.New("command not found")
+)
+
+// ExitCode documents an exit status of an App and the errors causing it.
+type ExitCode struct {
+	// The exit status passed to OsExiter
+	Code int
+	// Description of when the exit status is used, rendered in help and docs.
+	// Exit codes without a description are not documented.
+	Description string
+	// Sentinel errors mapped to Code, matched with errors.Is
+	Errors []error
+	// Match optionally maps further errors to Code, e.g. by their type
+	Match func(err error) bool
+}
+
+func (ec *ExitCode) matches(err error) bool {
+	for _, target := range ec.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return ec.Match != nil && ec.Match(err)
+}
+
+// ExitCodes is an ordered registry of exit codes. The first entry matching an
+// error determines its exit code, so more specific entries have to be listed
+// before more general ones.
+type ExitCodes []*ExitCode
+
+// DefaultExitCodes is a registry with reasonable exit codes for the errors
+// returned by an App. It is not used unless assigned to App.ExitCodes.
+var DefaultExitCodes = ExitCodes{
+	{Code: 0, Description: "Successful execution"},
+	{Code: 1, Description: "General error"},
+	{Code: 2, Description: "Missing required flags", Errors: []error{ErrRequiredFlags}},
+	{Code: 2, Description: "Incorrect usage, such as an unknown flag or an invalid flag value", Errors: []error{ErrUsage}},
+	{Code: 3, Description: "Unknown command or help topic", Errors: []error{ErrCommandNotFound}},
+	{Code: 124, Description: "Timed out", Errors: []error{context.DeadlineExceeded}},
+}
+
+// Lookup returns the exit code of the first entry matching err.
+func (ec ExitCodes) Lookup(err error) (int, bool) {
+	if err == nil {
+		return 0, false
+	}
+	for _, e := range ec {
+		if e != nil && e.matches(err) {
+			return e.Code, true
+		}
+	}
+	return 0, false
+}
+
+// Documented returns the entries that have a description.
+func (ec ExitCodes) Documented() ExitCodes {
+	var ret ExitCodes
+	for _, e := range ec {
+		if e != nil && e.Description != "" {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// apply attaches the registered exit codes to err. Errors with a registered
+// code are returned as ExitCoder, for MultiError each wrapped error is
+// handled separately. Errors without a registered code are returned as is.
+func (ec ExitCodes) apply(err error) error {
+	if len(ec) == 0 || err == nil {
+		return err
+	}
+	if code, ok := ec.Lookup(err); ok {
+		return &codedError{error: err, exitCode: code}
+	}
+	if multiErr, ok := err.(MultiError); ok {
+		errs := multiErr.Errors()
+		for i, merr := range errs {
+			errs[i] = ec.apply(merr)
+		}
+		return newMultiError(errs...)
+	}
+	return err
+}
+
+// MatchErrorType returns a function for ExitCode.Match reporting whether an
+// error in the chain has the same type as target, e.g.
+// MatchErrorType((*os.PathError)(nil)). The type of target has to implement
+// error.
+func MatchErrorType(target interface{}) func(err error) bool {
+	typ := reflect.TypeOf(target)
+	return func(err error) bool {
+		if typ == nil {
+			return false
+		}
+		return errors.As(err, reflect.New(typ).Interface())
+	}
+}
+
+// codedError is an error with an exit code assigned by ExitCodes
+type codedError struct {
+	error
+	exitCode int
+}
+
+func (e *codedError) ExitCode() int {
+	return e.exitCode
+}
+
+func (e *codedError) Unwrap() error {
+	return e.error
+}
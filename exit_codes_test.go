@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExitCodes_Lookup(t *testing.T) {
+	errCustom := errors.New("custom")
+	codes := ExitCodes{
+		{Code: 5, Errors: []error{errCustom}},
+		{Code: 6, Match: MatchErrorType((*os.PathError)(nil))},
+		{Code: 7, Errors: []error{ErrUsage}},
+	}
+
+	cases := []struct {
+		err      error
+		expected int
+		found    bool
+	}{
+		{err: nil, found: false},
+		{err: errors.New("unknown"), found: false},
+		{err: errCustom, expected: 5, found: true},
+		{err: fmt.Errorf("wrapped: %w", errCustom), expected: 5, found: true},
+		{err: &os.PathError{Op: "open", Path: "x", Err: os.ErrNotExist}, expected: 6, found: true},
+		{err: newUsageError(errors.New("bad flag")), expected: 7, found: true},
+		{err: &RequiredFlagsError{MissingFlags: []string{"foo"}}, expected: 7, found: true},
+	}
+
+	for _, c := range cases {
+		code, found := codes.Lookup(c.err)
+		expect(t, found, c.found)
+		expect(t, code, c.expected)
+	}
+}
+
+func TestExitCodes_Documented(t *testing.T) {
+	codes := ExitCodes{
+		{Code: 1, Description: "documented"},
+		{Code: 2},
+		nil,
+	}
+
+	documented := codes.Documented()
+	expect(t, len(documented), 1)
+	expect(t, documented[0].Code, 1)
+}
+
+func TestApp_ExitCodes(t *testing.T) {
+	required := &StringFlag{Name: "required", Required: true}
+	cases := []struct {
+		name     string
+		args     []string
+		action   ActionFunc
+		expected int
+	}{
+		{
+			name:     "usage error",
+			args:     []string{"app", "--nope"},
+			expected: 2,
+		},
+		{
+			name:     "usage error in command",
+			args:     []string{"app", "cmd", "--nope"},
+			expected: 2,
+		},
+		{
+			name:     "missing required flag in command",
+			args:     []string{"app", "req"},
+			expected: 2,
+		},
+		{
+			name:     "unknown help topic",
+			args:     []string{"app", "help", "nope"},
+			expected: 3,
+		},
+		{
+			name: "timeout",
+			args: []string{"app"},
+			action: func(*Context) error {
+				return fmt.Errorf("waiting: %w", context.DeadlineExceeded)
+			},
+			expected: 124,
+		},
+		{
+			name: "registry overrides ExitCoder",
+			args: []string{"app"},
+			action: func(*Context) error {
+				return Exit(context.DeadlineExceeded, 9)
+			},
+			expected: 124,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			code := -1
+			app := &App{
+				Writer:    &bytes.Buffer{},
+				ExitCodes: DefaultExitCodes,
+				ExitErrHandler: func(_ *Context, err error) {
+					if exitErr, ok := err.(ExitCoder); ok {
+						code = exitErr.ExitCode()
+					}
+				},
+				Action: c.action,
+				Commands: []*Command{
+					{Name: "cmd", Action: func(*Context) error { return nil }},
+					{Name: "req", Flags: []Flag{required}, Action: func(*Context) error { return nil }},
+				},
+			}
+
+			_ = app.Run(c.args)
+			expect(t, code, c.expected)
+		})
+	}
+}
+
+func TestApp_ExitCodes_NotSet(t *testing.T) {
+	called := false
+	app := &App{
+		Writer: &bytes.Buffer{},
+		ExitErrHandler: func(_ *Context, err error) {
+			if _, ok := err.(ExitCoder); ok {
+				called = true
+			}
+		},
+	}
+
+	_ = app.Run([]string{"app", "--nope"})
+	expect(t, called, false)
+}
+
+func TestApp_ExitCodes_NotSet_UsageErrors(t *testing.T) {
+	var handled []error
+	app := &App{
+		Writer: &bytes.Buffer{},
+		ExitErrHandler: func(_ *Context, err error) {
+			handled = append(handled, err)
+		},
+		Flags:  []Flag{&StringFlag{Name: "name", Required: true}},
+		Action: func(*Context) error { return nil },
+	}
+
+	expect(t, app.Run([]string{"app", "--nope"}) != nil, true)
+	expect(t, app.Run([]string{"app"}) != nil, true)
+	expect(t, len(handled), 0)
+}
+
+func TestApp_ExitCodes_Help(t *testing.T) {
+	output := &bytes.Buffer{}
+	app := &App{
+		Writer: output,
+		ExitCodes: ExitCodes{
+			{Code: 0, Description: "Successful execution"},
+			{Code: 42, Description: "The answer"},
+			{Code: 43},
+		},
+	}
+
+	_ = app.Run([]string{"app", "--help"})
+
+	if !strings.Contains(output.String(), "EXIT STATUS:\n   0   Successful execution\n   42  The answer\n") {
+		t.Errorf("expected EXIT STATUS section in help output, got:\n%s", output.String())
+	}
+	if strings.Contains(output.String(), "43") {
+		t.Errorf("expected undocumented exit code to be hidden, got:\n%s", output.String())
+	}
+}
+
+func TestToMarkdown_ExitCodes(t *testing.T) {
+	app := testApp()
+	app.ExitCodes = ExitCodes{
+		{Code: 0, Description: "Successful execution"},
+		{Code: 2, Description: "Incorrect usage"},
+	}
+
+	res, err := app.ToMarkdown()
+
+	expect(t, err, nil)
+	if !strings.HasSuffix(res, "# EXIT STATUS\n\n**0**: Successful execution\n\n**2**: Incorrect usage\n") {
+		t.Errorf("expected EXIT STATUS section in markdown, got:\n%s", res)
+	}
+}
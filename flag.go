@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -10,6 +9,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -51,11 +51,15 @@ var HelpFlag Flag = &BoolFlag{
 var FlagStringer FlagStringFunc = stringifyFlag
 
 var FlagsStringer = func(flags []Flag, indent int) []string {
+	return flagsStrings(flags, indent, FlagStringer, HelpWrapAt)
+}
+
+func flagsStrings(flags []Flag, indent int, flagStringer FlagStringFunc, wrapAt int) []string {
 	strs:=make([][2]string, len(flags))
 	maxTabPos := 0
 	indentStr := strings.Repeat(" ", indent)
 	for i, f := range flags {
-		str := indentStr + FlagStringer(f)
+		str := indentStr + flagStringer(f)
 		tabPos := strings.Index(str, "\t")
 		if tabPos > maxTabPos {
 			maxTabPos=tabPos
@@ -66,13 +70,17 @@ var FlagsStringer = func(flags []Flag, indent int) []string {
 	for i, s := range strs {
 		offs := maxTabPos+2
 		str := s[0]+strings.Repeat(" ", offs-len(s[0]))
-			str+=wrap(s[1], offs+2, HelpWrapAt)
+			str+=wrap(s[1], offs+2, wrapAt)
 		final[i] = str
 	}
 	return final
 }
 
 // Serializer is used to circumvent the limitations of flag.FlagSet.Set
+//
+// The built-in flags no longer need it, as all names of a flag share one
+// flag.Value. It is still used to copy the value of flags which define a
+// separate value for each name.
 type Serializer interface {
 	Serialize() string
 }
@@ -163,7 +171,46 @@ func flagSet(name string, flags []Flag) (*flag.FlagSet, error) {
 	return set, nil
 }
 
+// defineAliases defines the alias names of a flag for the flag.Value of its
+// first name, which has already been defined in set
+func defineAliases(set *flag.FlagSet, names []string, usage string) {
+	value := set.Lookup(names[0]).Value
+	for _, name := range names[1:] {
+		set.Var(value, name, usage)
+	}
+}
+
+// sameValue reports whether a and b are the same flag.Value instance
+func sameValue(a, b flag.Value) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// markFlag records the flag with the given name as set, without changing its
+// value, which it shares with another name of the same flag
+func markFlag(name string, set *flag.FlagSet) {
+	ff := set.Lookup(name)
+	value := ff.Value
+	ff.Value = markValue{}
+	_ = set.Set(name, "")
+	ff.Value = value
+}
+
+// markValue is a flag.Value ignoring any value it is set to
+type markValue struct{}
+
+func (markValue) String() string { return "" }
+
+func (markValue) Set(string) error { return nil }
+
 func copyFlag(name string, ff *flag.Flag, set *flag.FlagSet) {
+	if alias := set.Lookup(name); alias != nil && sameValue(alias.Value, ff.Value) {
+		markFlag(name, set)
+		return
+	}
+
 	switch ff.Value.(type) {
 	case Serializer:
 		_ = set.Set(name, ff.Value.(Serializer).Serialize())
@@ -172,7 +219,42 @@ func copyFlag(name string, ff *flag.Flag, set *flag.FlagSet) {
 	}
 }
 
-func normalizeFlags(flags []Flag, set *flag.FlagSet) error {
+// AliasPolicy selects how a flag taking a single value is handled if it is
+// used with several of its names, e.g. both as -n and --name. Flags with an
+// AccumulatingValue, like the slice flags, always collect the values given for
+// all of their names.
+type AliasPolicy int
+
+const (
+	// AliasError returns a FlagConflictError. This is the default.
+	AliasError AliasPolicy = iota
+	// AliasLastWins uses the value given last
+	AliasLastWins
+)
+
+// AccumulatingValue is a flag.Value collecting the values of all uses of its
+// flag, instead of keeping the last one
+type AccumulatingValue interface {
+	flag.Value
+
+	// Accumulates returns true if setting the value adds to it
+	Accumulates() bool
+}
+
+// combinesAliases reports whether the values given for the names a and b of
+// the same flag are combined instead of conflicting
+func combinesAliases(a, b *flag.Flag, policy AliasPolicy) bool {
+	// values defined separately for each name cannot be combined
+	if !sameValue(a.Value, b.Value) {
+		return false
+	}
+	if av, ok := a.Value.(AccumulatingValue); ok && av.Accumulates() {
+		return true
+	}
+	return policy == AliasLastWins
+}
+
+func normalizeFlags(flags []Flag, set *flag.FlagSet, policy AliasPolicy) error {
 	visited := make(map[string]bool)
 	set.Visit(func(f *flag.Flag) {
 		visited[f.Name] = true
@@ -185,11 +267,15 @@ func normalizeFlags(flags []Flag, set *flag.FlagSet) error {
 		var ff *flag.Flag
 		for _, name := range parts {
 			name = strings.Trim(name, " ")
-			if visited[name] {
-				if ff != nil {
-					return errors.New("Cannot use two forms of the same flag: " + name + " " + ff.Name)
-				}
+			if !visited[name] {
+				continue
+			}
+			if ff == nil {
 				ff = set.Lookup(name)
+				continue
+			}
+			if !combinesAliases(ff, set.Lookup(name), policy) {
+				return &FlagConflictError{Name: name, Other: ff.Name}
 			}
 		}
 		if ff == nil {
@@ -278,14 +364,17 @@ func withEnvHint(envVars []string, str string) string {
 }
 
 func flagNames(name string, aliases []string) []string {
-	var ret []string
+	ret := make([]string, 0, len(aliases)+1)
 
 	for _, part := range append([]string{name}, aliases...) {
 		// v1 -> v2 migration warning zone:
 		// Strip off anything after the first found comma or space, which
 		// *hopefully* makes it a tiny bit more obvious that unexpected behavior is
 		// caused by using the v1 form of stringly typed "Name".
-		ret = append(ret, commaWhitespace.ReplaceAllString(part, ""))
+		if strings.ContainsAny(part, ", ") {
+			part = commaWhitespace.ReplaceAllString(part, "")
+		}
+		ret = append(ret, part)
 	}
 
 	return ret
@@ -293,7 +382,7 @@ func flagNames(name string, aliases []string) []string {
 
 func flagStringSliceField(f Flag, name string) []string {
 	fv := flagValue(f)
-	field := fv.FieldByName(name)
+	field := flagField(fv, name)
 
 	if field.IsValid() {
 		return field.Interface().([]string)
@@ -318,6 +407,35 @@ func flagValue(f Flag) reflect.Value {
 	return fv
 }
 
+// flagFieldKey identifies a field of a flag type by name
+type flagFieldKey struct {
+	typ  reflect.Type
+	name string
+}
+
+// flagFields caches the index of the fields of flag types, which are looked up
+// by name each time a flag is shown in help
+var flagFields sync.Map
+
+// flagField returns the named field of the flag struct fv, or the zero Value
+// if it has no such field
+func flagField(fv reflect.Value, name string) reflect.Value {
+	key := flagFieldKey{fv.Type(), name}
+	index, ok := flagFields.Load(key)
+	if !ok {
+		var fieldIndex []int
+		if field, ok := key.typ.FieldByName(name); ok {
+			fieldIndex = field.Index
+		}
+		index, _ = flagFields.LoadOrStore(key, fieldIndex)
+	}
+
+	if fieldIndex := index.([]int); fieldIndex != nil {
+		return fv.FieldByIndex(fieldIndex)
+	}
+	return reflect.Value{}
+}
+
 func formatDefault(format string) string {
 	return " (default: " + format + ")"
 }
@@ -341,13 +459,16 @@ func stringifyFlag(f Flag) string {
 	case *ChoiceFlag:
 		return withEnvHint(flagStringSliceField(f, "EnvVars"),
 			stringifyChoiceFlag(f))
+	case defaultValuesFlag:
+		return withEnvHint(flagStringSliceField(f, "EnvVars"),
+			stringifySliceFlag(flagField(fv, "Usage").String(), f.Names(), f.defaultValues(), flagField(fv, "Placeholder").String()))
 	}
 
-	placeholder, usage := unquoteUsage(fv.FieldByName("Usage").String())
+	placeholder, usage := unquoteUsage(flagField(fv, "Usage").String())
 
 	needsPlaceholder := false
 	defaultValueString := ""
-	val := fv.FieldByName("Value")
+	val := flagField(fv, "Value")
 	hideDefaultValue := false
 
 	if boolFlag, ok := f.(*BoolFlag); ok {
@@ -356,17 +477,21 @@ func stringifyFlag(f Flag) string {
 
 	if val.IsValid() {
 		needsPlaceholder = val.Kind() != reflect.Bool
-		defaultValueString = fmt.Sprintf(formatDefault("%v"), val.Interface())
-		if hideDefaultValue {
+		value := fmt.Sprint(val.Interface())
+		if formatter, ok := f.(defaultValueFormatter); ok {
+			value = formatter.formatDefaultValue()
+		}
+		defaultValueString = fmt.Sprintf(formatDefault("%s"), value)
+		if hideDefaultValue || isNil(val) {
 			defaultValueString = ""
 		}
 
-		if val.Kind() == reflect.String && val.String() != "" {
-			defaultValueString = fmt.Sprintf(formatDefault("%q"), val.String())
+		if val.Kind() == reflect.String && value != "" {
+			defaultValueString = fmt.Sprintf(formatDefault("%q"), value)
 		}
 	}
 
-	helpText := fv.FieldByName("DefaultText")
+	helpText := flagField(fv, "DefaultText")
 	if helpText.IsValid() && helpText.String() != "" {
 		needsPlaceholder = val.Kind() != reflect.Bool
 		defaultValueString = fmt.Sprintf(formatDefault("%s"), helpText.String())
@@ -380,7 +505,7 @@ func stringifyFlag(f Flag) string {
 	}
 
 	if needsPlaceholder && placeholder == "" {
-		if pl := fv.FieldByName("Placeholder"); pl.IsValid() {
+		if pl := flagField(fv, "Placeholder"); pl.IsValid() {
 			placeholder = pl.String()
 		}
 	}
@@ -395,6 +520,20 @@ func stringifyFlag(f Flag) string {
 		fmt.Sprintf("%s\t%s", prefixedNames(f.Names(), placeholder), usageWithDefault))
 }
 
+// defaultValueFormatter is implemented by flags whose default value is shown
+// in help in their own format, such as 64KiB for a ByteSizeFlag, instead of
+// the fmt formatting of their Value field
+type defaultValueFormatter interface {
+	formatDefaultValue() string
+}
+
+// defaultValuesFlag is implemented by flags taking several values which are
+// not one of the slice flags above, such as TypedSliceFlag
+type defaultValuesFlag interface {
+	Flag
+	defaultValues() []string
+}
+
 func stringifyIntSliceFlag(f *IntSliceFlag) string {
 	var defaultVals []string
 	if f.Value != nil && len(f.Value.Value()) > 0 {
@@ -493,6 +632,22 @@ func quoteStrings(ss []string) []string {
 	return out
 }
 
+// appendFlag appends fl to flags without writing to the backing array of
+// flags, which belongs to the definition of an App or Command
+func appendFlag(flags []Flag, fl Flag) []Flag {
+	return append(flags[:len(flags):len(flags)], fl)
+}
+
+func removeFlag(flags []Flag, fl Flag) []Flag {
+	for i, existing := range flags {
+		if existing == fl {
+			return append(flags[:i:i], flags[i+1:]...)
+		}
+	}
+
+	return flags
+}
+
 func hasFlag(flags []Flag, fl Flag) bool {
 	for _, existing := range flags {
 		if fl == existing {
@@ -503,6 +658,14 @@ func hasFlag(flags []Flag, fl Flag) bool {
 	return false
 }
 
+// isSetFromEnvOrFile reports whether a flag takes its value from envVars or
+// filePath. Flags check this in IsSet instead of recording it in Apply, so that
+// applying a flag never writes to its definition.
+func isSetFromEnvOrFile(envVars []string, filePath string, allowEmpty bool) bool {
+	val, ok := flagFromEnvOrFile(envVars, filePath)
+	return ok && (allowEmpty || val != "")
+}
+
 func flagFromEnvOrFile(envVars []string, filePath string) (val string, ok bool) {
 	for _, envVar := range envVars {
 		envVar = strings.TrimSpace(envVar)
@@ -8,16 +8,18 @@ import (
 
 // BoolFlag is a flag with type bool
 type BoolFlag struct {
-	Name             string
-	Aliases          []string
-	Usage            string
-	EnvVars          []string
-	FilePath         string
-	Required         bool
-	Hidden           bool
-	Value            bool
-	DefaultText      string
-	Destination      *bool
+	Name        string
+	Aliases     []string
+	Usage       string
+	EnvVars     []string
+	FilePath    string
+	Required    bool
+	Hidden      bool
+	Value       bool
+	DefaultText string
+	Destination *bool
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet       bool
 	HideDefaultValue bool
 	Placeholder      string
@@ -25,7 +27,7 @@ type BoolFlag struct {
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *BoolFlag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
 }
 
 // String returns a readable representation of this value
@@ -67,6 +69,7 @@ func (f *BoolFlag) IsVisible() bool {
 
 // Apply populates the flag given the flag set and environment
 func (f *BoolFlag) Apply(set *flag.FlagSet) error {
+	value := f.Value
 	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
 		if val != "" {
 			valBool, err := strconv.ParseBool(val)
@@ -75,18 +78,17 @@ func (f *BoolFlag) Apply(set *flag.FlagSet) error {
 				return fmt.Errorf("could not parse %q as bool value for flag %s: %s", val, f.Name, err)
 			}
 
-			f.Value = valBool
-			f.HasBeenSet = true
+			value = valBool
 		}
 	}
 
-	for _, name := range f.Names() {
-		if f.Destination != nil {
-			set.BoolVar(f.Destination, name, f.Value, f.Usage)
-			continue
-		}
-		set.Bool(name, f.Value, f.Usage)
+	names := f.Names()
+	if f.Destination != nil {
+		set.BoolVar(f.Destination, names[0], value, f.Usage)
+	} else {
+		set.Bool(names[0], value, f.Usage)
 	}
+	defineAliases(set, names, f.Usage)
 
 	return nil
 }
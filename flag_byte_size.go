@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// byteSizeNumber matches the decimal numbers of byte sizes, rejecting the
+// prefixes, underscores and leading zeros accepted by big.Rat.SetString
+var byteSizeNumber = regexp.MustCompile(`^(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+
+// byteUnits are the SI and IEC units of byte sizes, largest first
+var byteUnits = []struct {
+	name string
+	size uint64
+}{
+	{"EiB", 1 << 60},
+	{"EB", 1e18},
+	{"PiB", 1 << 50},
+	{"PB", 1e15},
+	{"TiB", 1 << 40},
+	{"TB", 1e12},
+	{"GiB", 1 << 30},
+	{"GB", 1e9},
+	{"MiB", 1 << 20},
+	{"MB", 1e6},
+	{"KiB", 1 << 10},
+	{"kB", 1e3},
+	{"B", 1},
+}
+
+// ParseByteSize parses a number of bytes with an optional SI or IEC unit,
+// such as 512, 64KiB, 1.5GB or 10 MB. Units are case insensitive, so that
+// KB is a kilobyte of 1000 bytes and KiB a kibibyte of 1024 bytes.
+func ParseByteSize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	number := strings.TrimRightFunc(s, func(r rune) bool {
+		return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+	})
+	unit := s[len(number):]
+	number = strings.TrimSpace(number)
+
+	size := uint64(1)
+	if unit != "" {
+		found := false
+		for _, u := range byteUnits {
+			if strings.EqualFold(unit, u.name) {
+				size, found = u.size, true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown unit %q of byte size %q", unit, s)
+		}
+	}
+
+	if !byteSizeNumber.MatchString(number) {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	n, _ := new(big.Rat).SetString(number)
+	n.Mul(n, new(big.Rat).SetInt(new(big.Int).SetUint64(size)))
+	if !n.IsInt() {
+		return 0, fmt.Errorf("byte size %q is not a whole number of bytes", s)
+	}
+	if !n.Num().IsUint64() {
+		return 0, fmt.Errorf("byte size %q is too large", s)
+	}
+	return n.Num().Uint64(), nil
+}
+
+// FormatByteSize formats a number of bytes with the largest unit that takes
+// at most three decimals and which ParseByteSize parses back to the same
+// number, such as 64KiB or 1.5GB
+func FormatByteSize(n uint64) string {
+	for _, u := range byteUnits {
+		if n < u.size {
+			continue
+		}
+		s := strconv.FormatFloat(float64(n)/float64(u.size), 'f', -1, 64)
+		if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i-1 > 3 {
+			continue
+		}
+		if parsed, err := ParseByteSize(s + u.name); err == nil && parsed == n {
+			return s + u.name
+		}
+	}
+	return strconv.FormatUint(n, 10) + "B"
+}
+
+// ByteSizeFlag is a flag with a number of bytes as uint64, given with an
+// optional unit as parsed by ParseByteSize
+type ByteSizeFlag struct {
+	Name        string
+	Aliases     []string
+	Usage       string
+	EnvVars     []string
+	FilePath    string
+	Required    bool
+	Hidden      bool
+	Value       uint64
+	DefaultText string
+	Destination *uint64
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
+	HasBeenSet  bool
+	Placeholder string
+	// Min and Max bound the values of the flag, unless they are zero
+	Min uint64
+	Max uint64
+}
+
+// IsSet returns whether or not the flag has been set through env or file
+func (f *ByteSizeFlag) IsSet() bool {
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
+}
+
+// String returns a readable representation of this value
+// (for usage defaults)
+func (f *ByteSizeFlag) String() string {
+	return FlagStringer(f)
+}
+
+// Names returns the names of the flag
+func (f *ByteSizeFlag) Names() []string {
+	return flagNames(f.Name, f.Aliases)
+}
+
+// IsRequired returns whether or not the flag is required
+func (f *ByteSizeFlag) IsRequired() bool {
+	return f.Required
+}
+
+// TakesValue returns true of the flag takes a value, otherwise false
+func (f *ByteSizeFlag) TakesValue() bool {
+	return true
+}
+
+// GetUsage returns the usage string for the flag
+func (f *ByteSizeFlag) GetUsage() string {
+	return f.Usage
+}
+
+// IsVisible returns true if the flag is not hidden, otherwise false
+func (f *ByteSizeFlag) IsVisible() bool {
+	return !f.Hidden
+}
+
+// validateDefinition reports a ByteSizeFlag with a Min above its Max
+func (f *ByteSizeFlag) validateDefinition() error {
+	if f.Max != 0 && f.Min > f.Max {
+		return fmt.Errorf("min %s is above max %s of ByteSizeFlag", FormatByteSize(f.Min), FormatByteSize(f.Max))
+	}
+	return nil
+}
+
+// Apply populates the flag given the flag set and environment
+func (f *ByteSizeFlag) Apply(set *flag.FlagSet) error {
+	if err := f.validateDefinition(); err != nil {
+		return err
+	}
+
+	destination := f.Destination
+	if destination == nil {
+		destination = new(uint64)
+	}
+	*destination = f.Value
+	value := &byteSizeValue{destination: destination, min: f.Min, max: f.Max}
+
+	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
+		if val != "" {
+			if err := value.Set(val); err != nil {
+				return fmt.Errorf("could not parse %q as byte size value for flag %s: %s", val, f.Name, err)
+			}
+		}
+	}
+
+	names := f.Names()
+	set.Var(value, names[0], f.Usage)
+	defineAliases(set, names, f.Usage)
+
+	return nil
+}
+
+// GetValue returns the flags value as string representation and an empty
+// string if the flag takes no value at all.
+func (f *ByteSizeFlag) GetValue() string {
+	return FormatByteSize(f.Value)
+}
+
+// formatDefaultValue formats the default value for help like GetValue
+func (f *ByteSizeFlag) formatDefaultValue() string {
+	return f.GetValue()
+}
+
+// byteSizeValue is the flag.Value of a ByteSizeFlag
+type byteSizeValue struct {
+	destination *uint64
+	min, max    uint64
+}
+
+func (v *byteSizeValue) Set(s string) error {
+	n, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	if n < v.min {
+		return fmt.Errorf("byte size %s is below the minimum of %s", FormatByteSize(n), FormatByteSize(v.min))
+	}
+	if v.max != 0 && n > v.max {
+		return fmt.Errorf("byte size %s is above the maximum of %s", FormatByteSize(n), FormatByteSize(v.max))
+	}
+	*v.destination = n
+	return nil
+}
+
+func (v *byteSizeValue) String() string {
+	// the flag package calls String on zero values
+	if v.destination == nil {
+		return ""
+	}
+	return FormatByteSize(*v.destination)
+}
+
+func (v *byteSizeValue) Get() interface{} {
+	return *v.destination
+}
+
+// ByteSize looks up the value of a local ByteSizeFlag, returns
+// 0 if not found
+func (c *Context) ByteSize(name string) uint64 {
+	for _, ctx := range c.Lineage() {
+		if fs := ctx.lookupFlagSet(name); fs != nil {
+			if f := flagSetLookupWithValueSet(fs, name); f != nil {
+				return lookupByteSize(f)
+			}
+		}
+	}
+	return 0
+}
+
+func lookupByteSize(f *flag.Flag) uint64 {
+	if getter, ok := f.Value.(flag.Getter); ok {
+		if n, ok := getter.Get().(uint64); ok {
+			return n
+		}
+	}
+	return 0
+}
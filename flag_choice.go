@@ -112,6 +112,8 @@ type ChoiceFlag struct {
 	Required    bool
 	Hidden      bool
 	Destination interface{}
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Placeholder string
 }
@@ -121,31 +123,42 @@ func (f *ChoiceFlag) String() string {
 	return FlagStringer(f)
 }
 
-// Apply the value of the Flag to the cli.
-func (f *ChoiceFlag) Apply(set *flag.FlagSet) error {
+// validateDefinition reports a ChoiceFlag without a Choice
+func (f *ChoiceFlag) validateDefinition() error {
 	if f.Choice == nil {
 		return fmt.Errorf("choice must be provided for ChoiceFlag")
 	}
+	return nil
+}
+
+// Apply the value of the Flag to the cli.
+func (f *ChoiceFlag) Apply(set *flag.FlagSet) error {
+	if err := f.validateDefinition(); err != nil {
+		return err
+	}
 
+	value := f.Value
 	if v, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
 		v := f.Choice.FromString(v)
 		if v == nil {
 			return errParse
 		}
-		f.Value = v
-		f.HasBeenSet = true
+		value = v
 	}
 
-	for _, name := range f.Names() {
-		if f.Destination != nil {
-			v, err := newChoiceValueSwap(f.Choice, f.Value, f.Destination)
-			if err != nil {
-				return fmt.Errorf("failed to initialize new choice value swap: %w", err)
-			}
-			set.Var(v, name, f.Usage)
-			continue
+	// all names share one value
+	var v *choiceValue
+	if f.Destination != nil {
+		var err error
+		v, err = newChoiceValueSwap(f.Choice, value, f.Destination)
+		if err != nil {
+			return fmt.Errorf("failed to initialize new choice value swap: %w", err)
 		}
-		set.Var(newChoiceValue(f.Choice, f.Value), name, f.Usage)
+	} else {
+		v = newChoiceValue(f.Choice, value)
+	}
+	for _, name := range f.Names() {
+		set.Var(v, name, f.Usage)
 	}
 
 	return nil
@@ -158,7 +171,7 @@ func (f *ChoiceFlag) Names() []string {
 
 // IsSet Whether this cli.Flag has been set or not.
 func (f *ChoiceFlag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, true)
 }
 
 // IsRequired Whether this cli.Flag is required or not.
@@ -248,6 +261,15 @@ func (c *choiceValue) String() string {
 	return c.choice.ToString(interfaceOf(c.value))
 }
 
+// Accumulates returns true if the destination of the value is a slice
+func (c *choiceValue) Accumulates() bool {
+	switch c.value.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		return !c.value.IsNil() && c.value.Elem().Kind() == reflect.Slice
+	}
+	return false
+}
+
 func (c *choiceValue) Value() interface{} {
 	if !c.value.IsValid() || isNil(c.value) {
 		return nil
@@ -3,9 +3,208 @@ package cli
 import (
 	"flag"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// durationUnits are the units of the extended duration syntax
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond, // U+00B5 = micro symbol
+	"μs": time.Microsecond, // U+03BC = Greek letter mu
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  day,
+	"w":  week,
+}
+
+// ParseDuration parses a duration in the extended duration syntax, which adds
+// the units d for days of 24 hours and w for weeks of 7 days to the syntax of
+// time.ParseDuration, such as 7d or 1w2d12h, and takes ISO 8601 durations
+// such as P1DT2H, PT30M or P2W. Years and months of ISO 8601 durations are
+// rejected, as their length is not fixed.
+func ParseDuration(s string) (time.Duration, error) {
+	value := strings.TrimSpace(s)
+	neg := false
+	if value != "" && (value[0] == '-' || value[0] == '+') {
+		neg = value[0] == '-'
+		value = value[1:]
+	}
+
+	var d uint64
+	var err error
+	switch {
+	case value == "0":
+		return 0, nil
+	case value != "" && (value[0] == 'P' || value[0] == 'p'):
+		d, err = parseISODuration(value[1:], s)
+	default:
+		d, err = parseDurationParts(value, s, func(unit string) (string, error) {
+			if _, ok := durationUnits[unit]; !ok {
+				return "", fmt.Errorf("unknown unit %q of duration %q", unit, s)
+			}
+			return unit, nil
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+	if neg {
+		// the magnitude of math.MinInt64 wraps around to itself
+		return -time.Duration(d), nil
+	}
+	if d > math.MaxInt64 {
+		return 0, fmt.Errorf("duration %q is too large", s)
+	}
+	return time.Duration(d), nil
+}
+
+// maxDurationMagnitude is the magnitude of the smallest duration, which is one
+// more than that of the largest
+const maxDurationMagnitude = uint64(math.MaxInt64) + 1
+
+// parseISODuration parses the magnitude of an ISO 8601 duration following its
+// P designator
+func parseISODuration(value, s string) (uint64, error) {
+	value = strings.ToUpper(value)
+	date, clock := value, ""
+	if i := strings.IndexByte(value, 'T'); i >= 0 {
+		date, clock = value[:i], value[i+1:]
+		if clock == "" {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+	if date == "" && clock == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var d uint64
+	if date != "" {
+		// the units of each part must come in order
+		order := "YMWD"
+		dateDuration, err := parseDurationParts(strings.Replace(date, ",", ".", -1), s, func(unit string) (string, error) {
+			i := strings.Index(order, unit)
+			if len(unit) != 1 || i < 0 {
+				return "", fmt.Errorf("invalid duration %q", s)
+			}
+			if unit == "Y" || unit == "M" {
+				return "", fmt.Errorf("duration %q has years or months, which have no fixed length", s)
+			}
+			order = order[i+1:]
+			return strings.ToLower(unit), nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		d = dateDuration
+	}
+	if clock != "" {
+		order := "HMS"
+		clockDuration, err := parseDurationParts(strings.Replace(clock, ",", ".", -1), s, func(unit string) (string, error) {
+			i := strings.Index(order, unit)
+			if len(unit) != 1 || i < 0 {
+				return "", fmt.Errorf("invalid duration %q", s)
+			}
+			order = order[i+1:]
+			return strings.ToLower(unit), nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		if d > maxDurationMagnitude-clockDuration {
+			return 0, fmt.Errorf("duration %q is too large", s)
+		}
+		d += clockDuration
+	}
+	return d, nil
+}
+
+// parseDurationParts adds up the parts of the magnitude of a duration such as
+// 1w2d3h, each a decimal number and a unit, which unit checks and maps to a
+// unit of durationUnits
+func parseDurationParts(value, s string, unit func(string) (string, error)) (uint64, error) {
+	if value == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var d uint64
+	for value != "" {
+		i := strings.IndexFunc(value, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if i <= 0 || value[:i] == "." {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		j := strings.IndexFunc(value[i:], func(r rune) bool { return r >= '0' && r <= '9' || r == '.' })
+		if j < 0 {
+			j = len(value) - i
+		}
+		number := value[:i]
+		name, err := unit(value[i : i+j])
+		if err != nil {
+			return 0, err
+		}
+		value = value[i+j:]
+
+		// time.ParseDuration parses the decimals exactly, which only d and
+		// w need to be scaled from hours for
+		multiple := durationUnits[name] / time.Hour
+		if multiple > 1 {
+			name = "h"
+		} else {
+			multiple = 1
+		}
+		part, err := time.ParseDuration(number + name)
+		if err != nil || part > math.MaxInt64/multiple {
+			return 0, fmt.Errorf("duration %q is too large", s)
+		}
+		part *= multiple
+		if d > maxDurationMagnitude-uint64(part) {
+			return 0, fmt.Errorf("duration %q is too large", s)
+		}
+		d += uint64(part)
+	}
+	return d, nil
+}
+
+// FormatDuration formats a duration in the extended duration syntax parsed by
+// ParseDuration, using days or weeks for whole days and leaving out units
+// which are zero, such as 1w, 3d12h or 1h30m
+func FormatDuration(d time.Duration) string {
+	if d < 0 && d != math.MinInt64 {
+		return "-" + FormatDuration(-d)
+	}
+	if d < day {
+		// leave out the zero minutes and seconds of 1h0m0s
+		s := d.String()
+		if strings.HasSuffix(s, "m0s") {
+			s = strings.TrimSuffix(s, "0s")
+			if strings.HasSuffix(s, "h0m") {
+				s = strings.TrimSuffix(s, "0m")
+			}
+		}
+		return s
+	}
+
+	days := d / day
+	s := strconv.FormatInt(int64(days), 10) + "d"
+	if days%7 == 0 {
+		s = strconv.FormatInt(int64(days/7), 10) + "w"
+	}
+	if rest := d - days*day; rest > 0 {
+		s += FormatDuration(rest)
+	}
+	return s
+}
+
 // DurationFlag is a flag with type time.Duration (see https://golang.org/pkg/time/#ParseDuration)
 type DurationFlag struct {
 	Name        string
@@ -18,13 +217,24 @@ type DurationFlag struct {
 	Value       time.Duration
 	DefaultText string
 	Destination *time.Duration
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Placeholder string
+	// Extended enables the extended duration syntax of ParseDuration, with
+	// days, weeks and ISO 8601 durations, and FormatDuration for help
+	Extended bool
+	// Min and Max bound the values of the flag when they are not zero, or
+	// when HasMin or HasMax is set, such as for a Max of zero
+	Min    time.Duration
+	Max    time.Duration
+	HasMin bool
+	HasMax bool
 }
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *DurationFlag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
 }
 
 // String returns a readable representation of this value
@@ -56,39 +266,122 @@ func (f *DurationFlag) GetUsage() string {
 // GetValue returns the flags value as string representation and an empty
 // string if the flag takes no value at all.
 func (f *DurationFlag) GetValue() string {
+	if f.Extended {
+		return FormatDuration(f.Value)
+	}
 	return f.Value.String()
 }
 
+// formatDefaultValue formats the default value for help like GetValue
+func (f *DurationFlag) formatDefaultValue() string {
+	return f.GetValue()
+}
+
 // IsVisible returns true if the flag is not hidden, otherwise false
 func (f *DurationFlag) IsVisible() bool {
 	return !f.Hidden
 }
 
+// hasMin reports whether Min bounds the values of the flag
+func (f *DurationFlag) hasMin() bool {
+	return f.HasMin || f.Min != 0
+}
+
+// hasMax reports whether Max bounds the values of the flag
+func (f *DurationFlag) hasMax() bool {
+	return f.HasMax || f.Max != 0
+}
+
+// validateDefinition reports a DurationFlag with a Min above its Max
+func (f *DurationFlag) validateDefinition() error {
+	if f.hasMin() && f.hasMax() && f.Min > f.Max {
+		return fmt.Errorf("min %s is above max %s of DurationFlag", f.Min, f.Max)
+	}
+	return nil
+}
+
 // Apply populates the flag given the flag set and environment
 func (f *DurationFlag) Apply(set *flag.FlagSet) error {
+	if err := f.validateDefinition(); err != nil {
+		return err
+	}
+
+	destination := f.Destination
+	if destination == nil {
+		destination = new(time.Duration)
+	}
+	*destination = f.Value
+	value := &durationValue{destination: destination, extended: f.Extended}
+	if f.hasMin() {
+		min := f.Min
+		value.min = &min
+	}
+	if f.hasMax() {
+		max := f.Max
+		value.max = &max
+	}
+
 	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
 		if val != "" {
-			valDuration, err := time.ParseDuration(val)
-
-			if err != nil {
+			if err := value.Set(val); err != nil {
 				return fmt.Errorf("could not parse %q as duration value for flag %s: %s", val, f.Name, err)
 			}
-
-			f.Value = valDuration
-			f.HasBeenSet = true
 		}
 	}
 
-	for _, name := range f.Names() {
-		if f.Destination != nil {
-			set.DurationVar(f.Destination, name, f.Value, f.Usage)
-			continue
-		}
-		set.Duration(name, f.Value, f.Usage)
+	names := f.Names()
+	set.Var(value, names[0], f.Usage)
+	defineAliases(set, names, f.Usage)
+	return nil
+}
+
+// durationValue is the flag.Value of a DurationFlag
+type durationValue struct {
+	destination *time.Duration
+	extended    bool
+	// min and max are nil if the values are not bounded
+	min, max *time.Duration
+}
+
+func (v *durationValue) Set(s string) error {
+	parse := time.ParseDuration
+	if v.extended {
+		parse = ParseDuration
+	}
+	d, err := parse(s)
+	if err != nil {
+		return err
+	}
+	if v.min != nil && d < *v.min {
+		return fmt.Errorf("duration %s is below the minimum of %s", v.format(d), v.format(*v.min))
 	}
+	if v.max != nil && d > *v.max {
+		return fmt.Errorf("duration %s is above the maximum of %s", v.format(d), v.format(*v.max))
+	}
+	*v.destination = d
 	return nil
 }
 
+// format formats a duration in the syntax taken by the value
+func (v *durationValue) format(d time.Duration) string {
+	if v.extended {
+		return FormatDuration(d)
+	}
+	return d.String()
+}
+
+func (v *durationValue) String() string {
+	// the flag package calls String on zero values
+	if v.destination == nil {
+		return ""
+	}
+	return v.format(*v.destination)
+}
+
+func (v *durationValue) Get() interface{} {
+	return *v.destination
+}
+
 // Duration looks up the value of a local DurationFlag, returns
 // 0 if not found
 func (c *Context) Duration(name string) time.Duration {
@@ -103,7 +396,12 @@ func (c *Context) Duration(name string) time.Duration {
 }
 
 func lookupDuration(f *flag.Flag) time.Duration {
-	parsed, err := time.ParseDuration(f.Value.String())
+	if getter, ok := f.Value.(flag.Getter); ok {
+		if d, ok := getter.Get().(time.Duration); ok {
+			return d
+		}
+	}
+	parsed, err := ParseDuration(f.Value.String())
 	if err != nil {
 		return 0
 	}
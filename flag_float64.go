@@ -18,13 +18,15 @@ type Float64Flag struct {
 	Value       float64
 	DefaultText string
 	Destination *float64
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Placeholder string
 }
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *Float64Flag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
 }
 
 // String returns a readable representation of this value
@@ -66,6 +68,7 @@ func (f *Float64Flag) IsVisible() bool {
 
 // Apply populates the flag given the flag set and environment
 func (f *Float64Flag) Apply(set *flag.FlagSet) error {
+	value := f.Value
 	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
 		if val != "" {
 			valFloat, err := strconv.ParseFloat(val, 10)
@@ -74,18 +77,17 @@ func (f *Float64Flag) Apply(set *flag.FlagSet) error {
 				return fmt.Errorf("could not parse %q as float64 value for flag %s: %s", val, f.Name, err)
 			}
 
-			f.Value = valFloat
-			f.HasBeenSet = true
+			value = valFloat
 		}
 	}
 
-	for _, name := range f.Names() {
-		if f.Destination != nil {
-			set.Float64Var(f.Destination, name, f.Value, f.Usage)
-			continue
-		}
-		set.Float64(name, f.Value, f.Usage)
+	names := f.Names()
+	if f.Destination != nil {
+		set.Float64Var(f.Destination, names[0], value, f.Usage)
+	} else {
+		set.Float64(names[0], value, f.Usage)
 	}
+	defineAliases(set, names, f.Usage)
 
 	return nil
 }
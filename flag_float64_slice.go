@@ -57,7 +57,15 @@ func (f *Float64Slice) String() string {
 	return fmt.Sprintf("%#v", f.slice)
 }
 
+// Accumulates allows Float64Slice to fulfill AccumulatingValue
+func (f *Float64Slice) Accumulates() bool {
+	return true
+}
+
 // Serialize allows Float64Slice to fulfill Serializer
+//
+// Deprecated: all names of a flag share one value, so the flags no longer
+// copy slices between names through Serialize.
 func (f *Float64Slice) Serialize() string {
 	jsonBytes, _ := json.Marshal(f.slice)
 	return fmt.Sprintf("%s%s", slPfx, string(jsonBytes))
@@ -84,13 +92,15 @@ type Float64SliceFlag struct {
 	Hidden      bool
 	Value       *Float64Slice
 	DefaultText string
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Placeholder string
 }
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *Float64SliceFlag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
 }
 
 // String returns a readable representation of this value
@@ -135,27 +145,27 @@ func (f *Float64SliceFlag) IsVisible() bool {
 
 // Apply populates the flag given the flag set and environment
 func (f *Float64SliceFlag) Apply(set *flag.FlagSet) error {
+	copyValue := &Float64Slice{}
+	if f.Value != nil {
+		copyValue = f.Value.clone()
+	}
+
 	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
 		if val != "" {
-			f.Value = &Float64Slice{}
+			copyValue = &Float64Slice{}
 
 			for _, s := range strings.Split(val, ",") {
-				if err := f.Value.Set(strings.TrimSpace(s)); err != nil {
-					return fmt.Errorf("could not parse %q as float64 slice value for flag %s: %s", f.Value, f.Name, err)
+				if err := copyValue.Set(strings.TrimSpace(s)); err != nil {
+					return fmt.Errorf("could not parse %q as float64 slice value for flag %s: %s", val, f.Name, err)
 				}
 			}
 
 			// Set this to false so that we reset the slice if we then set values from
 			// flags that have already been set by the environment.
-			f.Value.hasBeenSet = false
-			f.HasBeenSet = true
+			copyValue.hasBeenSet = false
 		}
 	}
 
-	if f.Value == nil {
-		f.Value = &Float64Slice{}
-	}
-	copyValue := f.Value.clone()
 	for _, name := range f.Names() {
 		set.Var(copyValue, name, f.Usage)
 	}
@@ -23,13 +23,15 @@ type GenericFlag struct {
 	TakesFile   bool
 	Value       Generic
 	DefaultText string
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Placeholder string
 }
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *GenericFlag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
 }
 
 // String returns a readable representation of this value
@@ -80,8 +82,6 @@ func (f GenericFlag) Apply(set *flag.FlagSet) error {
 			if err := f.Value.Set(val); err != nil {
 				return fmt.Errorf("could not parse %q as value for flag %s: %s", val, f.Name, err)
 			}
-
-			f.HasBeenSet = true
 		}
 	}
 
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ParseHostPort parses a network address of the form host:port, such as
+// example.com:443, 192.0.2.1:80, [2001:db8::1]:53 or :8080. If defaultPort
+// is not zero, an address without a port gets defaultPort.
+func ParseHostPort(s string, defaultPort int) (string, error) {
+	address := strings.TrimSpace(s)
+	if address == "" {
+		return "", fmt.Errorf("%q is not a valid host:port address", s)
+	}
+	if defaultPort != 0 && (!strings.Contains(address, ":") ||
+		strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]")) {
+		host := strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
+		address = net.JoinHostPort(host, strconv.Itoa(defaultPort))
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a valid host:port address", s)
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return "", fmt.Errorf("port %q of address %q is not a number from 0 to 65535", port, s)
+	}
+	return net.JoinHostPort(host, strconv.FormatUint(n, 10)), nil
+}
+
+// HostPortFlag is a flag with a network address of the form host:port as
+// string. Addresses without a port get the DefaultPort, unless it is zero.
+type HostPortFlag struct {
+	Name        string
+	Aliases     []string
+	Usage       string
+	EnvVars     []string
+	FilePath    string
+	Required    bool
+	Hidden      bool
+	Value       string
+	DefaultText string
+	Destination *string
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
+	HasBeenSet  bool
+	Placeholder string
+	DefaultPort int
+}
+
+// IsSet returns whether or not the flag has been set through env or file
+func (f *HostPortFlag) IsSet() bool {
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
+}
+
+// String returns a readable representation of this value
+// (for usage defaults)
+func (f *HostPortFlag) String() string {
+	return FlagStringer(f)
+}
+
+// Names returns the names of the flag
+func (f *HostPortFlag) Names() []string {
+	return flagNames(f.Name, f.Aliases)
+}
+
+// IsRequired returns whether or not the flag is required
+func (f *HostPortFlag) IsRequired() bool {
+	return f.Required
+}
+
+// TakesValue returns true of the flag takes a value, otherwise false
+func (f *HostPortFlag) TakesValue() bool {
+	return true
+}
+
+// GetUsage returns the usage string for the flag
+func (f *HostPortFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetValue returns the flags value as string representation and an empty
+// string if the flag takes no value at all.
+func (f *HostPortFlag) GetValue() string {
+	return f.Value
+}
+
+// IsVisible returns true if the flag is not hidden, otherwise false
+func (f *HostPortFlag) IsVisible() bool {
+	return !f.Hidden
+}
+
+// validateDefinition reports a HostPortFlag with an invalid DefaultPort
+func (f *HostPortFlag) validateDefinition() error {
+	if f.DefaultPort < 0 || f.DefaultPort > 65535 {
+		return fmt.Errorf("default port %d of HostPortFlag is not a number from 0 to 65535", f.DefaultPort)
+	}
+	return nil
+}
+
+// Apply populates the flag given the flag set and environment
+func (f *HostPortFlag) Apply(set *flag.FlagSet) error {
+	if err := f.validateDefinition(); err != nil {
+		return err
+	}
+
+	destination := f.Destination
+	if destination == nil {
+		destination = new(string)
+	}
+	*destination = f.Value
+	value := &hostPortValue{destination: destination, defaultPort: f.DefaultPort}
+
+	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
+		if val != "" {
+			if err := value.Set(val); err != nil {
+				return fmt.Errorf("could not parse %q as host:port value for flag %s: %s", val, f.Name, err)
+			}
+		}
+	}
+
+	names := f.Names()
+	set.Var(value, names[0], f.Usage)
+	defineAliases(set, names, f.Usage)
+
+	return nil
+}
+
+// hostPortValue is the flag.Value of a HostPortFlag
+type hostPortValue struct {
+	destination *string
+	defaultPort int
+}
+
+func (v *hostPortValue) Set(s string) error {
+	address, err := ParseHostPort(s, v.defaultPort)
+	if err != nil {
+		return err
+	}
+	*v.destination = address
+	return nil
+}
+
+func (v *hostPortValue) String() string {
+	// the flag package calls String on zero values
+	if v.destination == nil {
+		return ""
+	}
+	return *v.destination
+}
+
+func (v *hostPortValue) Get() interface{} {
+	return *v.destination
+}
+
+// HostPort looks up the value of a local HostPortFlag, returns
+// "" if not found
+func (c *Context) HostPort(name string) string {
+	for _, ctx := range c.Lineage() {
+		if fs := ctx.lookupFlagSet(name); fs != nil {
+			if f := flagSetLookupWithValueSet(fs, name); f != nil {
+				return lookupHostPort(f)
+			}
+		}
+	}
+	return ""
+}
+
+func lookupHostPort(f *flag.Flag) string {
+	if getter, ok := f.Value.(flag.Getter); ok {
+		if address, ok := getter.Get().(string); ok {
+			return address
+		}
+	}
+	return ""
+}
+
+// validateDefinition reports a HostPortSliceFlag with an invalid DefaultPort
+func (f *HostPortSliceFlag) validateDefinition() error {
+	if f.DefaultPort < 0 || f.DefaultPort > 65535 {
+		return fmt.Errorf("default port %d of HostPortSliceFlag is not a number from 0 to 65535", f.DefaultPort)
+	}
+	return nil
+}
@@ -18,13 +18,15 @@ type IntFlag struct {
 	Value       int
 	DefaultText string
 	Destination *int
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Placeholder string
 }
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *IntFlag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
 }
 
 // String returns a readable representation of this value
@@ -66,6 +68,7 @@ func (f *IntFlag) IsVisible() bool {
 
 // Apply populates the flag given the flag set and environment
 func (f *IntFlag) Apply(set *flag.FlagSet) error {
+	value := f.Value
 	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
 		if val != "" {
 			valInt, err := strconv.ParseInt(val, 0, 64)
@@ -74,18 +77,17 @@ func (f *IntFlag) Apply(set *flag.FlagSet) error {
 				return fmt.Errorf("could not parse %q as int value for flag %s: %s", val, f.Name, err)
 			}
 
-			f.Value = int(valInt)
-			f.HasBeenSet = true
+			value = int(valInt)
 		}
 	}
 
-	for _, name := range f.Names() {
-		if f.Destination != nil {
-			set.IntVar(f.Destination, name, f.Value, f.Usage)
-			continue
-		}
-		set.Int(name, f.Value, f.Usage)
+	names := f.Names()
+	if f.Destination != nil {
+		set.IntVar(f.Destination, names[0], value, f.Usage)
+	} else {
+		set.Int(names[0], value, f.Usage)
 	}
+	defineAliases(set, names, f.Usage)
 
 	return nil
 }
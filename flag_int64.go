@@ -18,13 +18,15 @@ type Int64Flag struct {
 	Value       int64
 	DefaultText string
 	Destination *int64
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Placeholder string
 }
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *Int64Flag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
 }
 
 // String returns a readable representation of this value
@@ -66,6 +68,7 @@ func (f *Int64Flag) IsVisible() bool {
 
 // Apply populates the flag given the flag set and environment
 func (f *Int64Flag) Apply(set *flag.FlagSet) error {
+	value := f.Value
 	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
 		if val != "" {
 			valInt, err := strconv.ParseInt(val, 0, 64)
@@ -74,18 +77,17 @@ func (f *Int64Flag) Apply(set *flag.FlagSet) error {
 				return fmt.Errorf("could not parse %q as int value for flag %s: %s", val, f.Name, err)
 			}
 
-			f.Value = valInt
-			f.HasBeenSet = true
+			value = valInt
 		}
 	}
 
-	for _, name := range f.Names() {
-		if f.Destination != nil {
-			set.Int64Var(f.Destination, name, f.Value, f.Usage)
-			continue
-		}
-		set.Int64(name, f.Value, f.Usage)
+	names := f.Names()
+	if f.Destination != nil {
+		set.Int64Var(f.Destination, names[0], value, f.Usage)
+	} else {
+		set.Int64(names[0], value, f.Usage)
 	}
+	defineAliases(set, names, f.Usage)
 	return nil
 }
 
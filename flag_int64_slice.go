@@ -58,7 +58,15 @@ func (i *Int64Slice) String() string {
 	return fmt.Sprintf("%#v", i.slice)
 }
 
+// Accumulates allows Int64Slice to fulfill AccumulatingValue
+func (i *Int64Slice) Accumulates() bool {
+	return true
+}
+
 // Serialize allows Int64Slice to fulfill Serializer
+//
+// Deprecated: all names of a flag share one value, so the flags no longer
+// copy slices between names through Serialize.
 func (i *Int64Slice) Serialize() string {
 	jsonBytes, _ := json.Marshal(i.slice)
 	return fmt.Sprintf("%s%s", slPfx, string(jsonBytes))
@@ -85,13 +93,15 @@ type Int64SliceFlag struct {
 	Hidden      bool
 	Value       *Int64Slice
 	DefaultText string
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Placeholder string
 }
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *Int64SliceFlag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, true)
 }
 
 // String returns a readable representation of this value
@@ -136,25 +146,25 @@ func (f *Int64SliceFlag) IsVisible() bool {
 
 // Apply populates the flag given the flag set and environment
 func (f *Int64SliceFlag) Apply(set *flag.FlagSet) error {
+	copyValue := &Int64Slice{}
+	if f.Value != nil {
+		copyValue = f.Value.clone()
+	}
+
 	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
-		f.Value = &Int64Slice{}
+		copyValue = &Int64Slice{}
 
 		for _, s := range strings.Split(val, ",") {
-			if err := f.Value.Set(strings.TrimSpace(s)); err != nil {
+			if err := copyValue.Set(strings.TrimSpace(s)); err != nil {
 				return fmt.Errorf("could not parse %q as int64 slice value for flag %s: %s", val, f.Name, err)
 			}
 		}
 
 		// Set this to false so that we reset the slice if we then set values from
 		// flags that have already been set by the environment.
-		f.Value.hasBeenSet = false
-		f.HasBeenSet = true
+		copyValue.hasBeenSet = false
 	}
 
-	if f.Value == nil {
-		f.Value = &Int64Slice{}
-	}
-	copyValue := f.Value.clone()
 	for _, name := range f.Names() {
 		set.Var(copyValue, name, f.Usage)
 	}
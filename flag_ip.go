@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ParseIP parses an IPv4 or IPv6 address, such as 192.0.2.1 or 2001:db8::1
+func ParseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(strings.TrimSpace(s))
+	if ip == nil {
+		return nil, fmt.Errorf("%q is not a valid IP address", s)
+	}
+	return ip, nil
+}
+
+// IPFlag is a flag with type net.IP
+type IPFlag struct {
+	Name        string
+	Aliases     []string
+	Usage       string
+	EnvVars     []string
+	FilePath    string
+	Required    bool
+	Hidden      bool
+	Value       net.IP
+	DefaultText string
+	Destination *net.IP
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
+	HasBeenSet  bool
+	Placeholder string
+}
+
+// IsSet returns whether or not the flag has been set through env or file
+func (f *IPFlag) IsSet() bool {
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
+}
+
+// String returns a readable representation of this value
+// (for usage defaults)
+func (f *IPFlag) String() string {
+	return FlagStringer(f)
+}
+
+// Names returns the names of the flag
+func (f *IPFlag) Names() []string {
+	return flagNames(f.Name, f.Aliases)
+}
+
+// IsRequired returns whether or not the flag is required
+func (f *IPFlag) IsRequired() bool {
+	return f.Required
+}
+
+// TakesValue returns true of the flag takes a value, otherwise false
+func (f *IPFlag) TakesValue() bool {
+	return true
+}
+
+// GetUsage returns the usage string for the flag
+func (f *IPFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetValue returns the flags value as string representation and an empty
+// string if the flag takes no value at all.
+func (f *IPFlag) GetValue() string {
+	if f.Value != nil {
+		return f.Value.String()
+	}
+	return ""
+}
+
+// IsVisible returns true if the flag is not hidden, otherwise false
+func (f *IPFlag) IsVisible() bool {
+	return !f.Hidden
+}
+
+// Apply populates the flag given the flag set and environment
+func (f *IPFlag) Apply(set *flag.FlagSet) error {
+	destination := f.Destination
+	if destination == nil {
+		destination = new(net.IP)
+	}
+	*destination = f.Value
+	value := &ipValue{destination: destination}
+
+	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
+		if val != "" {
+			if err := value.Set(val); err != nil {
+				return fmt.Errorf("could not parse %q as IP value for flag %s: %s", val, f.Name, err)
+			}
+		}
+	}
+
+	names := f.Names()
+	set.Var(value, names[0], f.Usage)
+	defineAliases(set, names, f.Usage)
+
+	return nil
+}
+
+// ipValue is the flag.Value of an IPFlag
+type ipValue struct {
+	destination *net.IP
+}
+
+func (v *ipValue) Set(s string) error {
+	ip, err := ParseIP(s)
+	if err != nil {
+		return err
+	}
+	*v.destination = ip
+	return nil
+}
+
+func (v *ipValue) String() string {
+	// the flag package calls String on zero values
+	if v.destination == nil || *v.destination == nil {
+		return ""
+	}
+	return v.destination.String()
+}
+
+func (v *ipValue) Get() interface{} {
+	return *v.destination
+}
+
+// IP looks up the value of a local IPFlag, returns
+// nil if not found
+func (c *Context) IP(name string) net.IP {
+	for _, ctx := range c.Lineage() {
+		if fs := ctx.lookupFlagSet(name); fs != nil {
+			if f := flagSetLookupWithValueSet(fs, name); f != nil {
+				return lookupIP(f)
+			}
+		}
+	}
+	return nil
+}
+
+func lookupIP(f *flag.Flag) net.IP {
+	if getter, ok := f.Value.(flag.Getter); ok {
+		if ip, ok := getter.Get().(net.IP); ok {
+			return ip
+		}
+	}
+	return nil
+}
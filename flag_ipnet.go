@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ParseIPNet parses an IP network in CIDR notation, such as 192.0.2.0/24 or
+// 2001:db8::/32, returning the network of an address such as 192.0.2.1/24
+func ParseIPNet(s string) (*net.IPNet, error) {
+	_, ipNet, err := net.ParseCIDR(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid CIDR network such as 192.0.2.0/24", s)
+	}
+	return ipNet, nil
+}
+
+// IPNetFlag is a flag with type *net.IPNet, given in CIDR notation
+type IPNetFlag struct {
+	Name        string
+	Aliases     []string
+	Usage       string
+	EnvVars     []string
+	FilePath    string
+	Required    bool
+	Hidden      bool
+	Value       *net.IPNet
+	DefaultText string
+	Destination *net.IPNet
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
+	HasBeenSet  bool
+	Placeholder string
+}
+
+// IsSet returns whether or not the flag has been set through env or file
+func (f *IPNetFlag) IsSet() bool {
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
+}
+
+// String returns a readable representation of this value
+// (for usage defaults)
+func (f *IPNetFlag) String() string {
+	return FlagStringer(f)
+}
+
+// Names returns the names of the flag
+func (f *IPNetFlag) Names() []string {
+	return flagNames(f.Name, f.Aliases)
+}
+
+// IsRequired returns whether or not the flag is required
+func (f *IPNetFlag) IsRequired() bool {
+	return f.Required
+}
+
+// TakesValue returns true of the flag takes a value, otherwise false
+func (f *IPNetFlag) TakesValue() bool {
+	return true
+}
+
+// GetUsage returns the usage string for the flag
+func (f *IPNetFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetValue returns the flags value as string representation and an empty
+// string if the flag takes no value at all.
+func (f *IPNetFlag) GetValue() string {
+	if f.Value != nil {
+		return f.Value.String()
+	}
+	return ""
+}
+
+// IsVisible returns true if the flag is not hidden, otherwise false
+func (f *IPNetFlag) IsVisible() bool {
+	return !f.Hidden
+}
+
+// Apply populates the flag given the flag set and environment
+func (f *IPNetFlag) Apply(set *flag.FlagSet) error {
+	value := &ipNetValue{destination: f.Destination}
+	if f.Value != nil {
+		value.setIPNet(f.Value)
+	} else if f.Destination != nil {
+		*f.Destination = net.IPNet{}
+	}
+
+	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
+		if val != "" {
+			if err := value.Set(val); err != nil {
+				return fmt.Errorf("could not parse %q as IP network value for flag %s: %s", val, f.Name, err)
+			}
+		}
+	}
+
+	names := f.Names()
+	set.Var(value, names[0], f.Usage)
+	defineAliases(set, names, f.Usage)
+
+	return nil
+}
+
+// ipNetValue is the flag.Value of an IPNetFlag, which keeps the network it
+// was last set to and copies it to the destination, if any
+type ipNetValue struct {
+	ipNet       *net.IPNet
+	destination *net.IPNet
+}
+
+func (v *ipNetValue) setIPNet(ipNet *net.IPNet) {
+	copied := *ipNet
+	v.ipNet = &copied
+	if v.destination != nil {
+		*v.destination = copied
+	}
+}
+
+func (v *ipNetValue) Set(s string) error {
+	ipNet, err := ParseIPNet(s)
+	if err != nil {
+		return err
+	}
+	v.setIPNet(ipNet)
+	return nil
+}
+
+func (v *ipNetValue) String() string {
+	if v.ipNet == nil {
+		return ""
+	}
+	return v.ipNet.String()
+}
+
+func (v *ipNetValue) Get() interface{} {
+	return v.ipNet
+}
+
+// IPNet looks up the value of a local IPNetFlag, returns
+// nil if not found
+func (c *Context) IPNet(name string) *net.IPNet {
+	for _, ctx := range c.Lineage() {
+		if fs := ctx.lookupFlagSet(name); fs != nil {
+			if f := flagSetLookupWithValueSet(fs, name); f != nil {
+				return lookupIPNet(f)
+			}
+		}
+	}
+	return nil
+}
+
+func lookupIPNet(f *flag.Flag) *net.IPNet {
+	if getter, ok := f.Value.(flag.Getter); ok {
+		if ipNet, ok := getter.Get().(*net.IPNet); ok {
+			return ipNet
+		}
+	}
+	return nil
+}
@@ -15,13 +15,15 @@ type StringFlag struct {
 	Value       string
 	DefaultText string
 	Destination *string
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Placeholder string
 }
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *StringFlag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, true)
 }
 
 // String returns a readable representation of this value
@@ -63,18 +65,18 @@ func (f *StringFlag) IsVisible() bool {
 
 // Apply populates the flag given the flag set and environment
 func (f *StringFlag) Apply(set *flag.FlagSet) error {
+	value := f.Value
 	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
-		f.Value = val
-		f.HasBeenSet = true
+		value = val
 	}
 
-	for _, name := range f.Names() {
-		if f.Destination != nil {
-			set.StringVar(f.Destination, name, f.Value, f.Usage)
-			continue
-		}
-		set.String(name, f.Value, f.Usage)
+	names := f.Names()
+	if f.Destination != nil {
+		set.StringVar(f.Destination, names[0], value, f.Usage)
+	} else {
+		set.String(names[0], value, f.Usage)
 	}
+	defineAliases(set, names, f.Usage)
 
 	return nil
 }
@@ -52,7 +52,15 @@ func (s *StringSlice) String() string {
 	return fmt.Sprintf("%s", s.slice)
 }
 
+// Accumulates allows StringSlice to fulfill AccumulatingValue
+func (s *StringSlice) Accumulates() bool {
+	return true
+}
+
 // Serialize allows StringSlice to fulfill Serializer
+//
+// Deprecated: all names of a flag share one value, so the flags no longer
+// copy slices between names through Serialize.
 func (s *StringSlice) Serialize() string {
 	jsonBytes, _ := json.Marshal(s.slice)
 	return fmt.Sprintf("%s%s", slPfx, string(jsonBytes))
@@ -80,6 +88,8 @@ type StringSliceFlag struct {
 	TakesFile   bool
 	Value       *StringSlice
 	DefaultText string
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Destination *StringSlice
 	Placeholder string
@@ -87,7 +97,7 @@ type StringSliceFlag struct {
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *StringSliceFlag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, true)
 }
 
 // String returns a readable representation of this value
@@ -132,18 +142,20 @@ func (f *StringSliceFlag) IsVisible() bool {
 
 // Apply populates the flag given the flag set and environment
 func (f *StringSliceFlag) Apply(set *flag.FlagSet) error {
+	value := &StringSlice{}
+	if f.Value != nil {
+		value = f.Value.clone()
+	}
 
-	if f.Destination != nil && f.Value != nil {
-		f.Destination.slice = make([]string, len(f.Value.slice))
-		copy(f.Destination.slice, f.Value.slice)
-
+	if f.Destination != nil {
+		// values from a previous run are replaced, not appended to
+		f.Destination.slice = make([]string, len(value.slice))
+		copy(f.Destination.slice, value.slice)
+		f.Destination.hasBeenSet = false
 	}
 
 	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
-		if f.Value == nil {
-			f.Value = &StringSlice{}
-		}
-		destination := f.Value
+		destination := value
 		if f.Destination != nil {
 			destination = f.Destination
 		}
@@ -157,15 +169,11 @@ func (f *StringSliceFlag) Apply(set *flag.FlagSet) error {
 		// Set this to false so that we reset the slice if we then set values from
 		// flags that have already been set by the environment.
 		destination.hasBeenSet = false
-		f.HasBeenSet = true
 	}
 
-	if f.Value == nil {
-		f.Value = &StringSlice{}
-	}
 	setValue := f.Destination
 	if f.Destination == nil {
-		setValue = f.Value.clone()
+		setValue = value
 	}
 	for _, name := range f.Names() {
 		set.Var(setValue, name, f.Usage)
@@ -1,10 +1,14 @@
 package cli
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
+	"net"
+	"net/url"
 	"os"
 	"reflect"
 	"regexp"
@@ -89,6 +93,8 @@ func TestFlagsFromEnv(t *testing.T) {
 
 		{"1s", 1 * time.Second, &DurationFlag{Name: "time", EnvVars: []string{"TIME"}}, ""},
 		{"foobar", false, &DurationFlag{Name: "time", EnvVars: []string{"TIME"}}, `could not parse "foobar" as duration value for flag time: .*`},
+		{"7d", 0, &DurationFlag{Name: "time", EnvVars: []string{"TIME"}}, `could not parse "7d" as duration value for flag time: .*`},
+		{"P1DT2H", 26 * time.Hour, &DurationFlag{Name: "time", EnvVars: []string{"TIME"}, Extended: true}, ""},
 
 		{"1.2", 1.2, &Float64Flag{Name: "seconds", EnvVars: []string{"SECONDS"}}, ""},
 		{"1", 1.0, &Float64Flag{Name: "seconds", EnvVars: []string{"SECONDS"}}, ""},
@@ -127,6 +133,24 @@ func TestFlagsFromEnv(t *testing.T) {
 		{"foobar", 0, &Uint64Flag{Name: "seconds", EnvVars: []string{"SECONDS"}}, `could not parse "foobar" as uint64 value for flag seconds: .*`},
 
 		{"foo,bar", &Parser{"foo", "bar"}, &GenericFlag{Name: "names", Value: &Parser{}, EnvVars: []string{"NAMES"}}, ""},
+
+		{"64KiB", uint64(65536), &ByteSizeFlag{Name: "size", EnvVars: []string{"SIZE"}}, ""},
+		{"1.5 GB", uint64(1500000000), &ByteSizeFlag{Name: "size", EnvVars: []string{"SIZE"}}, ""},
+		{"1.2", 0, &ByteSizeFlag{Name: "size", EnvVars: []string{"SIZE"}}, `could not parse "1.2" as byte size value for flag size: .*`},
+		{"10MB", 0, &ByteSizeFlag{Name: "size", EnvVars: []string{"SIZE"}, Max: 1 << 20}, `could not parse "10MB" as byte size value for flag size: byte size 10MB is above the maximum of 1MiB`},
+
+		{"192.0.2.1", net.ParseIP("192.0.2.1"), &IPFlag{Name: "addr", EnvVars: []string{"ADDR"}}, ""},
+		{"192.0.2", nil, &IPFlag{Name: "addr", EnvVars: []string{"ADDR"}}, `could not parse "192.0.2" as IP value for flag addr: "192.0.2" is not a valid IP address`},
+		{"192.0.2.1, ::1", []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("::1")}, &IPSliceFlag{Name: "addrs", EnvVars: []string{"ADDRS"}}, ""},
+		{"10.0.0.0/8", mustParseIPNet("10.0.0.0/8"), &IPNetFlag{Name: "net", EnvVars: []string{"NET"}}, ""},
+		{"10.0.0.0", nil, &IPNetFlag{Name: "net", EnvVars: []string{"NET"}}, `could not parse "10.0.0.0" as IP network value for flag net: .*`},
+		{"10.0.0.0/8,fd00::/8", []*net.IPNet{mustParseIPNet("10.0.0.0/8"), mustParseIPNet("fd00::/8")}, &IPNetSliceFlag{Name: "nets", EnvVars: []string{"NETS"}}, ""},
+		{"example.com", "example.com:443", &HostPortFlag{Name: "server", EnvVars: []string{"SERVER"}, DefaultPort: 443}, ""},
+		{"example.com", nil, &HostPortFlag{Name: "server", EnvVars: []string{"SERVER"}}, `could not parse "example.com" as host:port value for flag server: "example.com" is not a valid host:port address`},
+		{"a:1,b", []string{"a:1", "b:80"}, &HostPortSliceFlag{Name: "servers", EnvVars: []string{"SERVERS"}, DefaultPort: 80}, ""},
+		{"https://example.com/x", mustParseURL("https://example.com/x"), &URLFlag{Name: "url", EnvVars: []string{"URL"}}, ""},
+		{"ftp://example.com", nil, &URLFlag{Name: "url", EnvVars: []string{"URL"}, Schemes: []string{"http", "https"}}, `could not parse "ftp://example.com" as URL value for flag url: scheme "ftp" of URL "ftp://example.com" is not one of http, https`},
+		{"http://a,http://b", []*url.URL{mustParseURL("http://a"), mustParseURL("http://b")}, &URLSliceFlag{Name: "urls", EnvVars: []string{"URLS"}}, ""},
 	}
 
 	for i, test := range flagTests {
@@ -406,7 +430,9 @@ func TestStringSliceFlagApply_UsesEnvValues(t *testing.T) {
 
 	err := set.Parse(nil)
 	expect(t, err, nil)
-	expect(t, val.Value(), NewStringSlice("vincent van goat", "scape goat").Value())
+	expect(t, set.Lookup("goat").Value.(*StringSlice).Value(), NewStringSlice("vincent van goat", "scape goat").Value())
+	expect(t, val.Value(), []string(nil))
+	expect(t, fl.IsSet(), true)
 }
 
 func TestStringSliceFlagApply_DefaultValueWithDestination(t *testing.T) {
@@ -622,6 +648,159 @@ func TestDurationFlagWithEnvVarHelpOutput(t *testing.T) {
 	}
 }
 
+func TestDurationFlagExtendedHelpOutput(t *testing.T) {
+	fl := &DurationFlag{Name: "retention", Value: 14 * 24 * time.Hour, Extended: true, Usage: "keep backups for `DURATION`"}
+	expect(t, fl.String(), "--retention DURATION\tkeep backups for DURATION (default: 2w)")
+	expect(t, fl.GetValue(), "2w")
+
+	fl = &DurationFlag{Name: "retention", Value: 14 * 24 * time.Hour, Placeholder: "value"}
+	expect(t, fl.String(), "--retention value\t(default: 336h0m0s)")
+}
+
+func TestDurationFlagBounds(t *testing.T) {
+	var retention time.Duration
+	app := &App{
+		Flags: []Flag{
+			&DurationFlag{Name: "retention", Value: 24 * time.Hour, Extended: true, Min: time.Hour, Max: 4 * 7 * 24 * time.Hour, Destination: &retention},
+		},
+		Action: func(*Context) error { return nil },
+	}
+
+	expect(t, app.Run([]string{"app", "--retention", "1w2d"}), nil)
+	expect(t, retention, 9*24*time.Hour)
+
+	err := app.Run([]string{"app", "--retention", "P5W"})
+	if err == nil || !strings.Contains(err.Error(), "duration 5w is above the maximum of 4w") {
+		t.Errorf("expected an error for a duration above the maximum, got %v", err)
+	}
+
+	err = app.Run([]string{"app", "--retention", "30m"})
+	if err == nil || !strings.Contains(err.Error(), "duration 30m is below the minimum of 1h") {
+		t.Errorf("expected an error for a duration below the minimum, got %v", err)
+	}
+
+	fl := &DurationFlag{Name: "retention", Min: time.Hour, Max: time.Minute}
+	err = fl.Apply(flag.NewFlagSet("test", 0))
+	expect(t, err.Error(), "min 1h0m0s is above max 1m0s of DurationFlag")
+}
+
+func TestDurationFlagZeroBounds(t *testing.T) {
+	set := flag.NewFlagSet("test", 0)
+	set.SetOutput(ioutil.Discard)
+	expect(t, (&DurationFlag{Name: "offset", Max: 0, HasMax: true}).Apply(set), nil)
+	expect(t, (&DurationFlag{Name: "delay", Min: 0, HasMin: true}).Apply(set), nil)
+	expect(t, (&DurationFlag{Name: "any"}).Apply(set), nil)
+
+	expect(t, set.Set("offset", "-1s"), nil)
+	expect(t, set.Set("offset", "1s").Error(), "duration 1s is above the maximum of 0s")
+	expect(t, set.Set("delay", "0s"), nil)
+	expect(t, set.Set("delay", "-1s").Error(), "duration -1s is below the minimum of 0s")
+	expect(t, set.Set("any", "-1s"), nil)
+
+	err := (&DurationFlag{Name: "bad", Min: time.Second, Max: 0, HasMax: true}).Apply(set)
+	expect(t, err.Error(), "min 1s is above max 0s of DurationFlag")
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		err      string
+	}{
+		{input: "0", expected: 0},
+		{input: "1h30m", expected: 90 * time.Minute},
+		{input: "1.5h", expected: 90 * time.Minute},
+		{input: "300ms", expected: 300 * time.Millisecond},
+		{input: "7d", expected: 7 * 24 * time.Hour},
+		{input: "2w", expected: 14 * 24 * time.Hour},
+		{input: "1w2d12h", expected: 9*24*time.Hour + 12*time.Hour},
+		{input: "1.5d", expected: 36 * time.Hour},
+		{input: "-2d", expected: -48 * time.Hour},
+		{input: "P1DT2H", expected: 26 * time.Hour},
+		{input: "PT30M", expected: 30 * time.Minute},
+		{input: "P2W", expected: 14 * 24 * time.Hour},
+		{input: "PT1.5S", expected: 1500 * time.Millisecond},
+		{input: "PT0,5H", expected: 30 * time.Minute},
+		{input: "p1dt1m", expected: 24*time.Hour + time.Minute},
+		{input: "-P1D", expected: -24 * time.Hour},
+		{input: "", err: `invalid duration ""`},
+		{input: "7", err: `invalid duration "7"`},
+		{input: "d", err: `invalid duration "d"`},
+		{input: "7x", err: `unknown unit "x" of duration "7x"`},
+		{input: "P1Y", err: `duration "P1Y" has years or months, which have no fixed length`},
+		{input: "P1M", err: `duration "P1M" has years or months, which have no fixed length`},
+		{input: "P", err: `invalid duration "P"`},
+		{input: "P1DT", err: `invalid duration "P1DT"`},
+		{input: "PT1H2H", err: `invalid duration "PT1H2H"`},
+		{input: "P1H", err: `invalid duration "P1H"`},
+		{input: "100000w", err: `duration "100000w" is too large`},
+		{input: "-2562047h47m16.854775808s", expected: math.MinInt64},
+		{input: "2562047h47m16.854775808s", err: `duration "2562047h47m16.854775808s" is too large`},
+		{input: "-2562047h47m16.854775809s", err: `duration "-2562047h47m16.854775809s" is too large`},
+	}
+
+	for _, test := range tests {
+		d, err := ParseDuration(test.input)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("expected error %q parsing %q, got %v", test.err, test.input, err)
+			}
+			continue
+		}
+		if err != nil || d != test.expected {
+			t.Errorf("expected %q to be parsed as %s, got %s and %v", test.input, test.expected, d, err)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "0s"},
+		{1500 * time.Millisecond, "1.5s"},
+		{time.Minute, "1m"},
+		{90 * time.Minute, "1h30m"},
+		{2 * time.Hour, "2h"},
+		{time.Hour + 5*time.Second, "1h0m5s"},
+		{24 * time.Hour, "1d"},
+		{36 * time.Hour, "1d12h"},
+		{7 * 24 * time.Hour, "1w"},
+		{10 * 24 * time.Hour, "10d"},
+		{-48 * time.Hour, "-2d"},
+		{math.MaxInt64, "106751d23h47m16.854775807s"},
+		{math.MinInt64 + 1, "-106751d23h47m16.854775807s"},
+		{math.MinInt64, "-2562047h47m16.854775808s"},
+	}
+
+	for _, test := range tests {
+		if got := FormatDuration(test.d); got != test.expected {
+			t.Errorf("expected %s to be formatted as %q, got %q", test.d, test.expected, got)
+		}
+		if parsed, err := ParseDuration(FormatDuration(test.d)); err != nil || parsed != test.d {
+			t.Errorf("expected %q to be parsed back as %s, got %s and %v", FormatDuration(test.d), test.d, parsed, err)
+		}
+	}
+}
+
+// durationText is a flag.Value without Get, holding a duration as text
+type durationText string
+
+func (d *durationText) Set(value string) error {
+	*d = durationText(value)
+	return nil
+}
+
+func (d *durationText) String() string {
+	return string(*d)
+}
+
+func TestLookupDurationExtended(t *testing.T) {
+	value := durationText("1w2d")
+	expect(t, lookupDuration(&flag.Flag{Name: "d", Value: &value}), 9*24*time.Hour)
+}
+
 func TestDurationFlagApply_SetsAllNames(t *testing.T) {
 	v := time.Second * 20
 	fl := DurationFlag{Name: "howmuch", Aliases: []string{"H", "whyyy"}, Destination: &v}
@@ -1883,6 +2062,17 @@ func TestStringSlice_Serialized_Set(t *testing.T) {
 	}
 }
 
+func TestSlices_SetKeepsSerializedLookingValues(t *testing.T) {
+	sl := NewStringSlice("a")
+	_ = sl.Set(`sl:::0:::["b"]`)
+	expect(t, sl.Value(), []string{`sl:::0:::["b"]`})
+
+	il := NewIntSlice(1)
+	if err := il.Set(`sl:::0:::[2]`); err == nil {
+		t.Errorf("expected an error setting an IntSlice to a serialized value, got %v", il.Value())
+	}
+}
+
 func TestIntSlice_Serialized_Set(t *testing.T) {
 	sl0 := NewIntSlice(1, 2)
 	ser0 := sl0.Serialize()
@@ -1915,6 +2105,237 @@ func TestInt64Slice_Serialized_Set(t *testing.T) {
 	}
 }
 
+func TestFlagAliasesShareValue(t *testing.T) {
+	var (
+		destString string
+		destBool   bool
+		destChoice string
+	)
+	flags := []Flag{
+		&BoolFlag{Name: "bool", Aliases: []string{"b1", "b2"}},
+		&BoolFlag{Name: "bool-dest", Aliases: []string{"bd"}, Destination: &destBool},
+		&DurationFlag{Name: "duration", Aliases: []string{"d"}},
+		&Float64Flag{Name: "float64", Aliases: []string{"f"}},
+		&IntFlag{Name: "int", Aliases: []string{"i"}},
+		&Int64Flag{Name: "int64", Aliases: []string{"i64"}},
+		&UintFlag{Name: "uint", Aliases: []string{"u"}},
+		&Uint64Flag{Name: "uint64", Aliases: []string{"u64"}},
+		&StringFlag{Name: "string", Aliases: []string{"s"}},
+		&StringFlag{Name: "string-dest", Aliases: []string{"sd"}, Destination: &destString},
+		&PathFlag{Name: "path", Aliases: []string{"p"}},
+		&ChoiceFlag{Name: "choice", Aliases: []string{"c"}, Choice: NewStringChoice("a", "b")},
+		&ChoiceFlag{Name: "choice-dest", Aliases: []string{"cd"}, Choice: NewStringChoice("a", "b"), Destination: &destChoice},
+		&StringSliceFlag{Name: "string-slice", Aliases: []string{"ss"}},
+		&IntSliceFlag{Name: "int-slice", Aliases: []string{"is"}},
+		&Int64SliceFlag{Name: "int64-slice", Aliases: []string{"i64s"}},
+		&Float64SliceFlag{Name: "float64-slice", Aliases: []string{"fs"}},
+		&TimestampFlag{Name: "timestamp", Aliases: []string{"ts"}, Layout: time.RFC3339},
+		&GenericFlag{Name: "generic", Aliases: []string{"g"}, Value: &Parser{}},
+	}
+
+	set, err := flagSet("test", flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range flags {
+		names := f.Names()
+		value := set.Lookup(names[0]).Value
+		for _, name := range names[1:] {
+			if !sameValue(set.Lookup(name).Value, value) {
+				t.Errorf("expected %s and %s to share one value", names[0], name)
+			}
+		}
+	}
+}
+
+func TestNormalizeFlags_SharedValues(t *testing.T) {
+	flags := []Flag{
+		&StringSliceFlag{Name: "tag", Aliases: []string{"t"}},
+		&StringFlag{Name: "name", Aliases: []string{"n"}},
+	}
+	set, _ := flagSet("test", flags)
+	// invalid UTF-8 did not survive the JSON copy of slices
+	_ = set.Parse([]string{"-t", "a", "-t", "\xbe", "-n", "\xff"})
+
+	if err := normalizeFlags(flags, set, AliasError); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, set.Lookup("tag").Value.(*StringSlice).Value(), []string{"a", "\xbe"})
+	expect(t, set.Lookup("name").Value.String(), "\xff")
+	expect(t, flagSetLookupWithValueSet(set, "tag") != nil, true)
+	expect(t, flagSetLookupWithValueSet(set, "name") != nil, true)
+}
+
+func TestNormalizeFlags_AliasPolicy(t *testing.T) {
+	cases := []struct {
+		name        string
+		flag        func() Flag
+		first       string
+		last        string
+		accumulates bool
+	}{
+		{name: "bool", flag: func() Flag { return &BoolFlag{Name: "long", Aliases: []string{"s"}} }, first: "true", last: "false"},
+		{name: "duration", flag: func() Flag { return &DurationFlag{Name: "long", Aliases: []string{"s"}} }, first: "1s", last: "2m"},
+		{name: "float64", flag: func() Flag { return &Float64Flag{Name: "long", Aliases: []string{"s"}} }, first: "1.5", last: "2.5"},
+		{name: "int", flag: func() Flag { return &IntFlag{Name: "long", Aliases: []string{"s"}} }, first: "1", last: "2"},
+		{name: "int64", flag: func() Flag { return &Int64Flag{Name: "long", Aliases: []string{"s"}} }, first: "1", last: "2"},
+		{name: "uint", flag: func() Flag { return &UintFlag{Name: "long", Aliases: []string{"s"}} }, first: "1", last: "2"},
+		{name: "uint64", flag: func() Flag { return &Uint64Flag{Name: "long", Aliases: []string{"s"}} }, first: "1", last: "2"},
+		{name: "byte size", flag: func() Flag { return &ByteSizeFlag{Name: "long", Aliases: []string{"s"}} }, first: "1KiB", last: "2KiB"},
+		{name: "ip", flag: func() Flag { return &IPFlag{Name: "long", Aliases: []string{"s"}} }, first: "192.0.2.1", last: "192.0.2.2"},
+		{name: "ip net", flag: func() Flag { return &IPNetFlag{Name: "long", Aliases: []string{"s"}} }, first: "10.0.0.0/8", last: "192.168.0.0/16"},
+		{name: "host port", flag: func() Flag { return &HostPortFlag{Name: "long", Aliases: []string{"s"}} }, first: "a:1", last: "b:2"},
+		{name: "url", flag: func() Flag { return &URLFlag{Name: "long", Aliases: []string{"s"}} }, first: "http://a", last: "http://b"},
+		{name: "string", flag: func() Flag { return &StringFlag{Name: "long", Aliases: []string{"s"}} }, first: "a", last: "b"},
+		{name: "path", flag: func() Flag { return &PathFlag{Name: "long", Aliases: []string{"s"}} }, first: "/a", last: "/b"},
+		{name: "timestamp", flag: func() Flag {
+			return &TimestampFlag{Name: "long", Aliases: []string{"s"}, Layout: "2006-01-02"}
+		}, first: "2020-01-01", last: "2021-01-01"},
+		{name: "generic", flag: func() Flag {
+			return &GenericFlag{Name: "long", Aliases: []string{"s"}, Value: &Parser{}}
+		}, first: "a,b", last: "c,d"},
+		{name: "choice", flag: func() Flag {
+			return &ChoiceFlag{Name: "long", Aliases: []string{"s"}, Choice: NewStringChoice("a", "b"), Destination: new(string)}
+		}, first: "a", last: "b"},
+		{name: "choice slice", flag: func() Flag {
+			return &ChoiceFlag{Name: "long", Aliases: []string{"s"}, Choice: NewStringChoice("a", "b"), Destination: &[]string{}}
+		}, first: "a", last: "b", accumulates: true},
+		{name: "string slice", flag: func() Flag { return &StringSliceFlag{Name: "long", Aliases: []string{"s"}} }, first: "a", last: "b", accumulates: true},
+		{name: "int slice", flag: func() Flag { return &IntSliceFlag{Name: "long", Aliases: []string{"s"}} }, first: "1", last: "2", accumulates: true},
+		{name: "int64 slice", flag: func() Flag { return &Int64SliceFlag{Name: "long", Aliases: []string{"s"}} }, first: "1", last: "2", accumulates: true},
+		{name: "float64 slice", flag: func() Flag { return &Float64SliceFlag{Name: "long", Aliases: []string{"s"}} }, first: "1.5", last: "2.5", accumulates: true},
+		{name: "ip slice", flag: func() Flag { return &IPSliceFlag{Name: "long", Aliases: []string{"s"}} }, first: "192.0.2.1", last: "192.0.2.2", accumulates: true},
+		{name: "url slice", flag: func() Flag { return &URLSliceFlag{Name: "long", Aliases: []string{"s"}} }, first: "http://a", last: "http://b", accumulates: true},
+	}
+
+	// parse returns the value of the flag after parsing args
+	parse := func(t *testing.T, f Flag, policy AliasPolicy, args ...string) (string, error) {
+		set, err := flagSet("test", []Flag{f})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := set.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		if err := normalizeFlags([]Flag{f}, set, policy); err != nil {
+			return "", err
+		}
+		value := set.Lookup("long").Value.String()
+		expect(t, set.Lookup("s").Value.String(), value)
+		return value, nil
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := []string{"-s=" + c.first, "--long=" + c.last}
+
+			// the values expected for the flag, when used with one name only
+			both, _ := parse(t, c.flag(), AliasError, "--long="+c.first, "--long="+c.last)
+			last, _ := parse(t, c.flag(), AliasError, "--long="+c.last)
+
+			value, err := parse(t, c.flag(), AliasError, args...)
+			if c.accumulates {
+				expect(t, err, nil)
+				expect(t, value, both)
+			} else {
+				var conflict *FlagConflictError
+				expect(t, errors.As(err, &conflict), true)
+				expect(t, conflict.Name, "s")
+				expect(t, conflict.Other, "long")
+			}
+
+			value, err = parse(t, c.flag(), AliasLastWins, args...)
+			expect(t, err, nil)
+			if c.accumulates {
+				expect(t, value, both)
+			} else {
+				expect(t, value, last)
+			}
+		})
+	}
+}
+
+// separateValuesFlag defines a separate value for each of its names, like
+// flags implemented outside of this package may do
+type separateValuesFlag struct {
+	StringFlag
+}
+
+func (f *separateValuesFlag) Apply(set *flag.FlagSet) error {
+	for _, name := range f.Names() {
+		set.String(name, f.Value, f.Usage)
+	}
+	return nil
+}
+
+func TestNormalizeFlags_SeparateValues(t *testing.T) {
+	flags := []Flag{
+		&separateValuesFlag{StringFlag{Name: "name", Aliases: []string{"n"}}},
+	}
+	set, _ := flagSet("test", flags)
+	_ = set.Parse([]string{"-n", "jerry"})
+
+	if err := normalizeFlags(flags, set, AliasError); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, set.Lookup("name").Value.String(), "jerry")
+	expect(t, flagSetLookupWithValueSet(set, "name") != nil, true)
+
+	// separate values cannot be combined
+	set, _ = flagSet("test", flags)
+	_ = set.Parse([]string{"-n", "jerry", "--name", "tom"})
+
+	var conflict *FlagConflictError
+	expect(t, errors.As(normalizeFlags(flags, set, AliasLastWins), &conflict), true)
+}
+
+func TestApp_AliasPolicy(t *testing.T) {
+	var (
+		tags []string
+		name string
+	)
+	newApp := func(policy AliasPolicy) *App {
+		return &App{
+			Writer:      ioutil.Discard,
+			AliasPolicy: policy,
+			Commands: []*Command{
+				{
+					Name: "cmd",
+					Subcommands: []*Command{
+						{
+							Name: "sub",
+							Flags: []Flag{
+								&StringSliceFlag{Name: "tag", Aliases: []string{"t"}},
+								&StringFlag{Name: "name", Aliases: []string{"n"}},
+							},
+							Action: func(c *Context) error {
+								tags = c.StringSlice("t")
+								name = c.String("name")
+								return nil
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	err := newApp(AliasError).Run([]string{"app", "cmd", "sub", "-t", "a", "--tag", "b"})
+	expect(t, err, nil)
+	expect(t, tags, []string{"a", "b"})
+
+	err = newApp(AliasError).Run([]string{"app", "cmd", "sub", "-n", "jerry", "--name", "tom"})
+	expect(t, errors.Is(err, ErrUsage), true)
+
+	err = newApp(AliasLastWins).Run([]string{"app", "cmd", "sub", "-n", "jerry", "--tag", "a", "--name", "tom", "-t", "b"})
+	expect(t, err, nil)
+	expect(t, name, "tom")
+	expect(t, tags, []string{"a", "b"})
+}
+
 func TestTimestamp_set(t *testing.T) {
 	ts := Timestamp{
 		timestamp:  nil,
@@ -1949,7 +2370,8 @@ func TestTimestampFlagApply(t *testing.T) {
 
 	err := set.Parse([]string{"--time", "2006-01-02T15:04:05Z"})
 	expect(t, err, nil)
-	expect(t, *fl.Value.timestamp, expectedResult)
+	expect(t, *set.Lookup("time").Value.(*Timestamp).Value(), expectedResult)
+	expect(t, fl.Value, (*Timestamp)(nil))
 }
 
 func TestTimestampFlagApplyValue(t *testing.T) {
@@ -1969,8 +2391,8 @@ func TestTimestampFlagApply_Fail_Parse_Wrong_Layout(t *testing.T) {
 	set.SetOutput(ioutil.Discard)
 	_ = fl.Apply(set)
 
-	err := set.Parse([]string{"--time", "2006-01-02T15:04:05Z"})
-	expect(t, err, fmt.Errorf("invalid value \"2006-01-02T15:04:05Z\" for flag -time: parsing time \"2006-01-02T15:04:05Z\" as \"randomlayout\": cannot parse \"2006-01-02T15:04:05Z\" as \"randomlayout\""))
+	err := set.Parse([]string{"--time", "2006-01-02 15:04:05"})
+	expect(t, err, fmt.Errorf("invalid value \"2006-01-02 15:04:05\" for flag -time: parsing time \"2006-01-02 15:04:05\" as \"randomlayout\": cannot parse \"2006-01-02 15:04:05\" as \"randomlayout\""))
 }
 
 func TestTimestampFlagApply_Fail_Parse_Wrong_Time(t *testing.T) {
@@ -1979,8 +2401,108 @@ func TestTimestampFlagApply_Fail_Parse_Wrong_Time(t *testing.T) {
 	set.SetOutput(ioutil.Discard)
 	_ = fl.Apply(set)
 
-	err := set.Parse([]string{"--time", "2006-01-02T15:04:05Z"})
-	expect(t, err, fmt.Errorf("invalid value \"2006-01-02T15:04:05Z\" for flag -time: parsing time \"2006-01-02T15:04:05Z\" as \"Jan 2, 2006 at 3:04pm (MST)\": cannot parse \"2006-01-02T15:04:05Z\" as \"Jan\""))
+	err := set.Parse([]string{"--time", "2006-01-02 15:04:05"})
+	expect(t, err, fmt.Errorf("invalid value \"2006-01-02 15:04:05\" for flag -time: parsing time \"2006-01-02 15:04:05\" as \"Jan 2, 2006 at 3:04pm (MST)\": cannot parse \"2006-01-02 15:04:05\" as \"Jan\""))
+}
+
+func TestTimestampFlag_Layouts(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	fl := &TimestampFlag{Name: "at", Layout: "2006-01-02 15:04", Layouts: []string{"2006-01-02", time.RFC3339}, Location: berlin}
+
+	for input, expected := range map[string]time.Time{
+		"2024-03-01 10:30":     time.Date(2024, 3, 1, 10, 30, 0, 0, berlin),
+		"2024-03-01":           time.Date(2024, 3, 1, 0, 0, 0, 0, berlin),
+		"2024-03-01T10:30:00Z": time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+	} {
+		set := flag.NewFlagSet("test", 0)
+		_ = fl.Apply(set)
+		if err := set.Parse([]string{"--at", input}); err != nil {
+			t.Errorf("expected %q to be parsed, got %v", input, err)
+			continue
+		}
+		if got := set.Lookup("at").Value.(*Timestamp).Value(); !got.Equal(expected) {
+			t.Errorf("expected %q to be parsed as %s, got %s", input, expected, got)
+		}
+	}
+
+	set := flag.NewFlagSet("test", 0)
+	set.SetOutput(ioutil.Discard)
+	_ = fl.Apply(set)
+	err := set.Parse([]string{"--at", "March 1st"})
+	expect(t, err.Error(), `invalid value "March 1st" for flag -at: cannot parse "March 1st" as timestamp in any of the layouts ["2006-01-02 15:04" "2006-01-02" "2006-01-02T15:04:05Z07:00"]`)
+}
+
+func TestTimestampFlag_DefaultLayout(t *testing.T) {
+	fl := &TimestampFlag{Name: "at"}
+	set := flag.NewFlagSet("test", 0)
+	expect(t, fl.Apply(set), nil)
+	expect(t, set.Parse([]string{"--at", "2024-03-01T10:30:00+01:00"}), nil)
+	expect(t, set.Lookup("at").Value.(*Timestamp).Value().Unix(), time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC).Unix())
+}
+
+func TestTimestamp_RFC3339Fallback(t *testing.T) {
+	ts := &Timestamp{}
+	ts.SetLayouts("2006-01-02")
+	expect(t, ts.Set("2024-01-01T00:00:00Z"), nil)
+	expect(t, ts.Value().Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), true)
+
+	fl := &TimestampFlag{Name: "at", Layout: "2006-01-02"}
+	set := flag.NewFlagSet("test", 0)
+	set.SetOutput(ioutil.Discard)
+	expect(t, fl.Apply(set), nil)
+	expect(t, set.Parse([]string{"--at", "2024-03-01T10:30:00+01:00"}), nil)
+	expect(t, set.Lookup("at").Value.(*Timestamp).Value().Unix(), time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC).Unix())
+	err := set.Parse([]string{"--at", "March 1st"})
+	expect(t, err.Error(), `invalid value "March 1st" for flag -at: parsing time "March 1st" as "2006-01-02": cannot parse "March 1st" as "2006"`)
+}
+
+func TestTimestampFlag_Relative(t *testing.T) {
+	defer func() { timeNow = time.Now }()
+	timeNow = func() time.Time { return time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC) }
+	location := time.FixedZone("EST", -5*3600)
+
+	for input, expected := range map[string]time.Time{
+		"now":              time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC),
+		"-2h":              time.Date(2024, 3, 10, 13, 30, 0, 0, time.UTC),
+		"+1w":              time.Date(2024, 3, 17, 15, 30, 0, 0, time.UTC),
+		"today":            time.Date(2024, 3, 10, 0, 0, 0, 0, location),
+		"yesterday":        time.Date(2024, 3, 9, 0, 0, 0, 0, location),
+		"tomorrow+12h":     time.Date(2024, 3, 11, 12, 0, 0, 0, location),
+		"now-P1D":          time.Date(2024, 3, 9, 15, 30, 0, 0, time.UTC),
+		"2024-01-01+3d":    time.Date(2024, 1, 4, 0, 0, 0, 0, location),
+		"2024-01-01 - 1w":  time.Date(2023, 12, 25, 0, 0, 0, 0, location),
+		"2024-01-01-1d12h": time.Date(2023, 12, 30, 12, 0, 0, 0, location),
+	} {
+		ts := &Timestamp{}
+		ts.SetLayouts("2006-01-02")
+		ts.SetLocation(location)
+		if err := ts.Set(input); err != nil {
+			t.Errorf("expected %q to be parsed, got %v", input, err)
+			continue
+		}
+		if got := ts.Value(); !got.Equal(expected) {
+			t.Errorf("expected %q to be parsed as %s, got %s", input, expected, got)
+		}
+	}
+
+	ts := &Timestamp{}
+	ts.SetLayout("2006-01-02")
+	err := ts.Set("2024-01-01+3x")
+	expect(t, err.Error(), `parsing time "2024-01-01+3x": extra text: "+3x"`)
+}
+
+func TestTimestampFlagHelpOutput(t *testing.T) {
+	at := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	fl := &TimestampFlag{Name: "since", Layout: "2006-01-02 15:04", Value: NewTimestamp(at), Usage: "show logs since `TIME`"}
+	expect(t, fl.String(), "--since TIME\tshow logs since TIME (default: 2024-03-01 09:30)")
+	expect(t, fl.GetValue(), "2024-03-01 09:30")
+
+	fl = &TimestampFlag{Name: "since", Value: NewTimestamp(at), Location: time.FixedZone("CET", 3600), Placeholder: "value"}
+	expect(t, fl.String(), "--since value\t(default: 2024-03-01T10:30:00+01:00)")
+
+	fl = &TimestampFlag{Name: "since", Placeholder: "value"}
+	expect(t, fl.String(), "--since value\t")
+	expect(t, (&Timestamp{}).String(), "")
 }
 
 type flagDefaultTestCase struct {
@@ -2034,6 +2556,30 @@ func TestFlagDefaultValue(t *testing.T) {
 			toParse: []string{"--flag", "13"},
 			expect: `--flag value	(default: 1)`,
 		},
+		&flagDefaultTestCase{
+			name:    "byte size",
+			flag:    &ByteSizeFlag{Name: "flag", Value: 1 << 20, Placeholder: "value"},
+			toParse: []string{"--flag", "2GiB"},
+			expect: `--flag value	(default: 1MiB)`,
+		},
+		&flagDefaultTestCase{
+			name:    "ip",
+			flag:    &IPFlag{Name: "flag", Value: net.ParseIP("127.0.0.1"), Placeholder: "value"},
+			toParse: []string{"--flag", "::1"},
+			expect: `--flag value	(default: 127.0.0.1)`,
+		},
+		&flagDefaultTestCase{
+			name:    "url",
+			flag:    &URLFlag{Name: "flag", Value: mustParseURL("https://example.com"), Placeholder: "value"},
+			toParse: []string{"--flag", "https://example.org"},
+			expect: `--flag value	(default: https://example.com)`,
+		},
+		&flagDefaultTestCase{
+			name:    "url without default",
+			flag:    &URLFlag{Name: "flag", Placeholder: "value"},
+			toParse: []string{"--flag", "https://example.org"},
+			expect: `--flag value	`,
+		},
 	}
 	for i, v := range cases {
 		set := flag.NewFlagSet("test", 0)
@@ -2059,3 +2605,291 @@ func TestTimestampFlagApply_WithDestination(t *testing.T) {
 	expect(t, err, nil)
 	expect(t, *fl.Destination.timestamp, expectedResult)
 }
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected uint64
+		err      string
+	}{
+		{input: "512", expected: 512},
+		{input: "0", expected: 0},
+		{input: "512B", expected: 512},
+		{input: "64KiB", expected: 64 << 10},
+		{input: "64kib", expected: 64 << 10},
+		{input: "1.5GB", expected: 1500000000},
+		{input: "10MB", expected: 10000000},
+		{input: "10 MB", expected: 10000000},
+		{input: "2kB", expected: 2000},
+		{input: "2KB", expected: 2000},
+		{input: "1.5KiB", expected: 1536},
+		{input: "16EiB", err: `byte size "16EiB" is too large`},
+		{input: "1.5B", err: `byte size "1.5B" is not a whole number of bytes`},
+		{input: "-1KB", err: `invalid byte size "-1KB"`},
+		{input: "1e3", err: `invalid byte size "1e3"`},
+		{input: "KB", err: `invalid byte size "KB"`},
+		{input: "0x10", err: `invalid byte size "0x10"`},
+		{input: "0b101", err: `invalid byte size "0b101"`},
+		{input: "1_000", err: `invalid byte size "1_000"`},
+		{input: "010", err: `invalid byte size "010"`},
+		{input: "1/2KB", err: `invalid byte size "1/2KB"`},
+		{input: ".5KB", err: `invalid byte size ".5KB"`},
+		{input: "10XB", err: `unknown unit "XB" of byte size "10XB"`},
+	}
+
+	for _, test := range tests {
+		n, err := ParseByteSize(test.input)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("expected error %q parsing %q, got %v", test.err, test.input, err)
+			}
+			continue
+		}
+		if err != nil || n != test.expected {
+			t.Errorf("expected %q to be parsed as %d, got %d and %v", test.input, test.expected, n, err)
+		}
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := []struct {
+		n        uint64
+		expected string
+	}{
+		{0, "0B"},
+		{512, "512B"},
+		{1000, "1kB"},
+		{1024, "1KiB"},
+		{1536, "1.5KiB"},
+		{1500000000, "1.5GB"},
+		{64 << 20, "64MiB"},
+		{1234567, "1234.567kB"},
+		{2000, "2kB"},
+		{1<<64 - 1, "18446744073709551615B"},
+	}
+
+	for _, test := range tests {
+		if got := FormatByteSize(test.n); got != test.expected {
+			t.Errorf("expected %d to be formatted as %q, got %q", test.n, test.expected, got)
+		}
+		if parsed, err := ParseByteSize(FormatByteSize(test.n)); err != nil || parsed != test.n {
+			t.Errorf("expected %q to be parsed back as %d, got %d and %v", FormatByteSize(test.n), test.n, parsed, err)
+		}
+	}
+}
+
+func TestByteSizeFlagHelpOutput(t *testing.T) {
+	fl := &ByteSizeFlag{Name: "max-size", Value: 64 << 20, Usage: "the maximum `SIZE`"}
+	expect(t, fl.String(), "--max-size SIZE\tthe maximum SIZE (default: 64MiB)")
+	expect(t, fl.GetValue(), "64MiB")
+}
+
+func TestByteSizeFlagBounds(t *testing.T) {
+	var size uint64
+	app := &App{
+		Flags: []Flag{
+			&ByteSizeFlag{Name: "size", Value: 1 << 10, Min: 1 << 10, Max: 1 << 30, Destination: &size},
+		},
+		Action: func(*Context) error { return nil },
+	}
+
+	expect(t, app.Run([]string{"app", "--size", "1MiB"}), nil)
+	expect(t, size, uint64(1<<20))
+
+	err := app.Run([]string{"app", "--size", "512"})
+	if err == nil || !strings.Contains(err.Error(), "byte size 512B is below the minimum of 1KiB") {
+		t.Errorf("expected an error for a size below the minimum, got %v", err)
+	}
+
+	fl := &ByteSizeFlag{Name: "size", Min: 2, Max: 1}
+	err = fl.Apply(flag.NewFlagSet("test", 0))
+	expect(t, err.Error(), "min 2B is above max 1B of ByteSizeFlag")
+}
+
+func TestContext_ByteSize(t *testing.T) {
+	set := flag.NewFlagSet("test", 0)
+	_ = (&ByteSizeFlag{Name: "size"}).Apply(set)
+	_ = set.Parse([]string{"--size", "2MB"})
+	parentSet := flag.NewFlagSet("test", 0)
+	_ = (&ByteSizeFlag{Name: "top"}).Apply(parentSet)
+	_ = parentSet.Parse([]string{"--top", "1KiB"})
+	parentCtx := NewContext(nil, parentSet, nil)
+	c := NewContext(nil, set, parentCtx)
+	expect(t, c.ByteSize("size"), uint64(2000000))
+	expect(t, c.ByteSize("top"), uint64(1024))
+	expect(t, c.ByteSize("missing"), uint64(0))
+}
+
+func mustParseIPNet(s string) *net.IPNet {
+	ipNet, err := ParseIPNet(s)
+	if err != nil {
+		panic(err)
+	}
+	return ipNet
+}
+
+func mustParseURL(s string) *url.URL {
+	u, err := ParseURL(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func TestParseIPNet(t *testing.T) {
+	expect(t, mustParseIPNet("192.0.2.1/24").String(), "192.0.2.0/24")
+	expect(t, mustParseIPNet("2001:db8::1/32").String(), "2001:db8::/32")
+
+	_, err := ParseIPNet("192.0.2.1")
+	expect(t, err.Error(), `"192.0.2.1" is not a valid CIDR network such as 192.0.2.0/24`)
+}
+
+func TestParseHostPort(t *testing.T) {
+	tests := []struct {
+		input       string
+		defaultPort int
+		expected    string
+		err         string
+	}{
+		{input: "example.com:443", expected: "example.com:443"},
+		{input: "example.com", defaultPort: 80, expected: "example.com:80"},
+		{input: "example.com:8080", defaultPort: 80, expected: "example.com:8080"},
+		{input: ":8080", expected: ":8080"},
+		{input: "192.0.2.1:053", expected: "192.0.2.1:53"},
+		{input: "[2001:db8::1]:53", expected: "[2001:db8::1]:53"},
+		{input: "[2001:db8::1]", defaultPort: 53, expected: "[2001:db8::1]:53"},
+		{input: "example.com", err: `"example.com" is not a valid host:port address`},
+		{input: "2001:db8::1", defaultPort: 53, err: `"2001:db8::1" is not a valid host:port address`},
+		{input: "", defaultPort: 80, err: `"" is not a valid host:port address`},
+		{input: "example.com:http", err: `port "http" of address "example.com:http" is not a number from 0 to 65535`},
+		{input: "example.com:65536", err: `port "65536" of address "example.com:65536" is not a number from 0 to 65535`},
+	}
+
+	for _, test := range tests {
+		address, err := ParseHostPort(test.input, test.defaultPort)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("expected error %q parsing %q, got %v", test.err, test.input, err)
+			}
+			continue
+		}
+		if err != nil || address != test.expected {
+			t.Errorf("expected %q to be parsed as %q, got %q and %v", test.input, test.expected, address, err)
+		}
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	u, err := ParseURL("HTTPS://example.com/path?q=1", "http", "https")
+	expect(t, err, nil)
+	expect(t, u.Host, "example.com")
+
+	_, err = ParseURL("example.com/path")
+	expect(t, err.Error(), `"example.com/path" is not an absolute URL with a scheme such as https://`)
+
+	_, err = ParseURL("localhost:8080")
+	expect(t, err.Error(), `"localhost:8080" is not an absolute URL with a scheme such as https://`)
+
+	u, err = ParseURL("file:///etc/hosts")
+	expect(t, err, nil)
+	expect(t, u.Path, "/etc/hosts")
+
+	_, err = ParseURL("http://[::1")
+	expect(t, err.Error(), `"http://[::1" is not a valid URL`)
+
+	_, err = ParseURL("file:///etc/hosts", "http", "https")
+	expect(t, err.Error(), `scheme "file" of URL "file:///etc/hosts" is not one of http, https`)
+}
+
+func TestNetFlagsApply_WithDestination(t *testing.T) {
+	var (
+		ip     net.IP
+		ipNet  net.IPNet
+		server string
+		u      url.URL
+		ips    []net.IP
+		urls   []*url.URL
+	)
+	app := &App{
+		Flags: []Flag{
+			&IPFlag{Name: "ip", Destination: &ip},
+			&IPNetFlag{Name: "net", Value: mustParseIPNet("10.0.0.0/8"), Destination: &ipNet},
+			&HostPortFlag{Name: "server", DefaultPort: 443, Destination: &server},
+			&URLFlag{Name: "url", Destination: &u},
+			&IPSliceFlag{Name: "ips", Value: []net.IP{net.ParseIP("::1")}, Destination: &ips},
+			&URLSliceFlag{Name: "urls", Destination: &urls},
+		},
+		Action: func(*Context) error { return nil },
+	}
+
+	err := app.Run([]string{"app", "--ip", "192.0.2.1", "--server", "example.com", "--url", "https://example.com/a",
+		"--ips", "192.0.2.1", "--ips", "192.0.2.2", "--urls", "http://a", "--urls", "http://b"})
+	expect(t, err, nil)
+	expect(t, ip.String(), "192.0.2.1")
+	expect(t, ipNet.String(), "10.0.0.0/8")
+	expect(t, server, "example.com:443")
+	expect(t, u.String(), "https://example.com/a")
+	expect(t, ips, []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("192.0.2.2")})
+	expect(t, urls, []*url.URL{mustParseURL("http://a"), mustParseURL("http://b")})
+
+	// the next run starts from the defaults again
+	expect(t, app.Run([]string{"app"}), nil)
+	expect(t, ip, net.IP(nil))
+	expect(t, ips, []net.IP{net.ParseIP("::1")})
+	expect(t, len(urls), 0)
+}
+
+func TestNetFlagsParseErrors(t *testing.T) {
+	app := &App{
+		Flags: []Flag{
+			&IPFlag{Name: "ip"},
+			&URLSliceFlag{Name: "urls", Schemes: []string{"https"}},
+		},
+		Action: func(*Context) error { return nil },
+	}
+
+	err := app.Run([]string{"app", "--ip", "localhost"})
+	expect(t, err.Error(), `invalid value "localhost" for flag -ip: "localhost" is not a valid IP address`)
+
+	err = app.Run([]string{"app", "--urls", "https://a", "--urls", "http://b"})
+	expect(t, err.Error(), `invalid value "http://b" for flag -urls: scheme "http" of URL "http://b" is not one of https`)
+}
+
+func TestNetSliceFlagHelpOutput(t *testing.T) {
+	fl := &IPNetSliceFlag{Name: "allow", Value: []*net.IPNet{mustParseIPNet("10.0.0.0/8"), mustParseIPNet("fd00::/8")}, Usage: "allowed `CIDR` networks"}
+	expect(t, fl.String(), "--allow CIDR\tallowed CIDR networks (default: 10.0.0.0/8, fd00::/8)\t(accepts multiple inputs)")
+	expect(t, fl.GetValue(), "10.0.0.0/8, fd00::/8")
+
+	hp := &HostPortSliceFlag{Name: "peer", Value: []string{"a:1", "b:2"}, Placeholder: "HOST:PORT"}
+	expect(t, hp.String(), "--peer HOST:PORT\t(default: a:1, b:2)\t(accepts multiple inputs)")
+}
+
+func TestHostPortFlagInvalidDefaultPort(t *testing.T) {
+	fl := &HostPortFlag{Name: "server", DefaultPort: 70000}
+	err := fl.Apply(flag.NewFlagSet("test", 0))
+	expect(t, err.Error(), "default port 70000 of HostPortFlag is not a number from 0 to 65535")
+}
+
+func TestContext_NetFlags(t *testing.T) {
+	set := flag.NewFlagSet("test", 0)
+	_ = (&IPFlag{Name: "ip"}).Apply(set)
+	_ = (&IPNetSliceFlag{Name: "nets"}).Apply(set)
+	_ = (&HostPortSliceFlag{Name: "peers", DefaultPort: 7946}).Apply(set)
+	_ = set.Parse([]string{"--ip", "::1", "--nets", "10.0.0.0/8", "--peers", "a", "--peers", "b:1"})
+	parentSet := flag.NewFlagSet("test", 0)
+	_ = (&IPNetFlag{Name: "net"}).Apply(parentSet)
+	_ = (&HostPortFlag{Name: "server"}).Apply(parentSet)
+	_ = (&URLFlag{Name: "url"}).Apply(parentSet)
+	_ = parentSet.Parse([]string{"--net", "192.0.2.0/24", "--server", "a:1", "--url", "https://example.com"})
+	parentCtx := NewContext(nil, parentSet, nil)
+	c := NewContext(nil, set, parentCtx)
+	expect(t, c.IP("ip"), net.ParseIP("::1"))
+	expect(t, c.IPNetSlice("nets"), []*net.IPNet{mustParseIPNet("10.0.0.0/8")})
+	expect(t, c.HostPortSlice("peers"), []string{"a:7946", "b:1"})
+	expect(t, c.IPNet("net"), mustParseIPNet("192.0.2.0/24"))
+	expect(t, c.HostPort("server"), "a:1")
+	expect(t, c.URL("url"), mustParseURL("https://example.com"))
+	expect(t, c.IP("missing"), net.IP(nil))
+	expect(t, c.IPSlice("missing"), []net.IP(nil))
+	expect(t, c.URLSlice("missing"), []*url.URL(nil))
+}
@@ -3,14 +3,21 @@ package cli
 import (
 	"flag"
 	"fmt"
+	"strings"
 	"time"
 )
 
+// timeNow returns the current time for relative timestamps
+var timeNow = time.Now
+
 // Timestamp wrap to satisfy golang's flag interface.
 type Timestamp struct {
 	timestamp  *time.Time
 	hasBeenSet bool
 	layout     string
+	// layouts are tried after layout, in order
+	layouts  []string
+	location *time.Location
 }
 
 // Timestamp constructor
@@ -29,11 +36,28 @@ func (t *Timestamp) SetTimestamp(value time.Time) {
 // Set the timestamp string layout for future parsing
 func (t *Timestamp) SetLayout(layout string) {
 	t.layout = layout
+	t.layouts = nil
+}
+
+// SetLayouts sets the timestamp string layouts for future parsing, which are
+// tried in order
+func (t *Timestamp) SetLayouts(layouts ...string) {
+	t.layout = ""
+	t.layouts = layouts
+	if len(layouts) > 0 {
+		t.layout, t.layouts = layouts[0], layouts[1:]
+	}
+}
+
+// SetLocation sets the location of timestamps parsed without a time zone,
+// which is UTC if the location is nil
+func (t *Timestamp) SetLocation(location *time.Location) {
+	t.location = location
 }
 
 // Parses the string value to timestamp
 func (t *Timestamp) Set(value string) error {
-	timestamp, err := time.Parse(t.layout, value)
+	timestamp, err := t.parse(value)
 	if err != nil {
 		return err
 	}
@@ -43,9 +67,112 @@ func (t *Timestamp) Set(value string) error {
 	return nil
 }
 
+// parse parses a timestamp in one of the layouts, or a time relative to now,
+// today, yesterday, tomorrow or a timestamp, such as -2h or 2024-01-01+3d
+func (t *Timestamp) parse(value string) (time.Time, error) {
+	timestamp, err := t.parseBase(value)
+	if err == nil {
+		return timestamp, nil
+	}
+
+	// the offset follows the last + or - which starts a duration
+	for i := len(value) - 1; i >= 0; i-- {
+		if value[i] != '+' && value[i] != '-' {
+			continue
+		}
+		offset, durationErr := ParseDuration(value[i:i+1] + strings.TrimSpace(value[i+1:]))
+		if durationErr != nil {
+			continue
+		}
+		if strings.TrimSpace(value[:i]) == "" {
+			return timeNow().In(t.getLocation()).Add(offset), nil
+		}
+		if base, baseErr := t.parseBase(strings.TrimSpace(value[:i])); baseErr == nil {
+			return base.Add(offset), nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// parseBase parses a timestamp in one of the layouts or one of the words now,
+// today, yesterday and tomorrow
+func (t *Timestamp) parseBase(value string) (time.Time, error) {
+	location := t.getLocation()
+	now := timeNow().In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "now":
+		return now, nil
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	// RFC3339 is tried after the layouts set, but left out of the errors
+	layouts := t.getLayouts()
+	tried := layouts
+	if !hasLayout(layouts, time.RFC3339) {
+		tried = append(layouts[:len(layouts):len(layouts)], time.RFC3339)
+	}
+	var firstErr error
+	for _, layout := range tried {
+		timestamp, err := time.ParseInLocation(layout, value, location)
+		if err == nil {
+			return timestamp, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if len(layouts) == 1 {
+		return time.Time{}, firstErr
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as timestamp in any of the layouts %q", value, layouts)
+}
+
+// getLayouts returns the layouts to parse timestamps with, which are RFC3339
+// if none are set
+func (t *Timestamp) getLayouts() []string {
+	var layouts []string
+	if t.layout != "" {
+		layouts = append(layouts, t.layout)
+	}
+	layouts = append(layouts, t.layouts...)
+	if len(layouts) == 0 {
+		return []string{time.RFC3339}
+	}
+	return layouts
+}
+
+func hasLayout(layouts []string, layout string) bool {
+	for _, l := range layouts {
+		if l == layout {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Timestamp) getLocation() *time.Location {
+	if t.location == nil {
+		return time.UTC
+	}
+	return t.location
+}
+
 // String returns a readable representation of this value (for usage defaults)
 func (t *Timestamp) String() string {
-	return fmt.Sprintf("%#v", t.timestamp)
+	if t.timestamp == nil {
+		return ""
+	}
+	timestamp := *t.timestamp
+	if t.location != nil {
+		timestamp = timestamp.In(t.location)
+	}
+	return timestamp.Format(t.getLayouts()[0])
 }
 
 // Value returns the timestamp value stored in the flag
@@ -70,14 +197,22 @@ type TimestampFlag struct {
 	Layout      string
 	Value       *Timestamp
 	DefaultText string
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Destination *Timestamp
 	Placeholder string
+	// Layouts are tried after Layout in order, and RFC3339 is used if
+	// neither is given
+	Layouts []string
+	// Location is the location of timestamps without a time zone, which is
+	// UTC if it is nil
+	Location *time.Location
 }
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *TimestampFlag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, true)
 }
 
 // String returns a readable representation of this value
@@ -110,35 +245,49 @@ func (f *TimestampFlag) GetUsage() string {
 // string if the flag takes no value at all.
 func (f *TimestampFlag) GetValue() string {
 	if f.Value != nil {
-		return f.Value.timestamp.String()
+		value := *f.Value
+		f.configure(&value)
+		return value.String()
 	}
 	return ""
 }
 
+// formatDefaultValue formats the default value for help like GetValue
+func (f *TimestampFlag) formatDefaultValue() string {
+	return f.GetValue()
+}
+
 // IsVisible returns true if the flag is not hidden, otherwise false
 func (f *TimestampFlag) IsVisible() bool {
 	return !f.Hidden
 }
 
+// configure sets the layouts and the location of the flag on a value
+func (f *TimestampFlag) configure(value *Timestamp) {
+	value.layout = f.Layout
+	value.layouts = f.Layouts
+	value.location = f.Location
+}
+
 // Apply populates the flag given the flag set and environment
 func (f *TimestampFlag) Apply(set *flag.FlagSet) error {
-	if f.Layout == "" {
-		return fmt.Errorf("timestamp Layout is required")
-	}
-	if f.Value == nil {
-		f.Value = &Timestamp{}
+	value := &Timestamp{}
+	if f.Value != nil {
+		*value = *f.Value
 	}
-	f.Value.SetLayout(f.Layout)
+	f.configure(value)
 
 	if f.Destination != nil {
-		f.Destination.SetLayout(f.Layout)
+		// like the destinations of other flags, start each run from the default
+		f.Destination.timestamp = value.timestamp
+		f.Destination.hasBeenSet = false
+		f.configure(f.Destination)
 	}
 
 	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
-		if err := f.Value.Set(val); err != nil {
+		if err := value.Set(val); err != nil {
 			return fmt.Errorf("could not parse %q as timestamp value for flag %s: %s", val, f.Name, err)
 		}
-		f.HasBeenSet = true
 	}
 
 	for _, name := range f.Names() {
@@ -147,7 +296,7 @@ func (f *TimestampFlag) Apply(set *flag.FlagSet) error {
 			continue
 		}
 
-		set.Var(f.Value, name, f.Usage)
+		set.Var(value, name, f.Usage)
 	}
 	return nil
 }
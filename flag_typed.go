@@ -0,0 +1,389 @@
+//go:build go1.18
+// +build go1.18
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValueParser parses the values of a TypedFlag or TypedSliceFlag given on the
+// command line, in the environment or in a file, and formats them for help
+type ValueParser[T any] interface {
+	Parse(value string) (T, error)
+	Format(value T) string
+}
+
+// ParserFunc is a ValueParser formatting values with fmt.Sprint, so that a
+// new type of flag only takes a function parsing its values
+type ParserFunc[T any] func(value string) (T, error)
+
+// Parse calls the function
+func (p ParserFunc[T]) Parse(value string) (T, error) {
+	return p(value)
+}
+
+// Format returns the value formatted with fmt.Sprint
+func (p ParserFunc[T]) Format(value T) string {
+	return fmt.Sprint(value)
+}
+
+// The ValueParsers of the types of the other flags in this package, for use
+// with TypedFlag and TypedSliceFlag
+var (
+	IntParser ValueParser[int] = ParserFunc[int](func(value string) (int, error) {
+		parsed, err := strconv.ParseInt(value, 0, strconv.IntSize)
+		return int(parsed), err
+	})
+	Int64Parser ValueParser[int64] = ParserFunc[int64](func(value string) (int64, error) {
+		return strconv.ParseInt(value, 0, 64)
+	})
+	UintParser ValueParser[uint] = ParserFunc[uint](func(value string) (uint, error) {
+		parsed, err := strconv.ParseUint(value, 0, strconv.IntSize)
+		return uint(parsed), err
+	})
+	Uint64Parser ValueParser[uint64] = ParserFunc[uint64](func(value string) (uint64, error) {
+		return strconv.ParseUint(value, 0, 64)
+	})
+	Float64Parser ValueParser[float64] = ParserFunc[float64](func(value string) (float64, error) {
+		return strconv.ParseFloat(value, 64)
+	})
+	StringParser ValueParser[string] = ParserFunc[string](func(value string) (string, error) {
+		return value, nil
+	})
+	DurationParser ValueParser[time.Duration] = ParserFunc[time.Duration](time.ParseDuration)
+)
+
+// TimestampParser returns a ValueParser of timestamps in the given layout, as
+// used by time.Parse
+func TimestampParser(layout string) ValueParser[time.Time] {
+	return timestampParser(layout)
+}
+
+type timestampParser string
+
+func (layout timestampParser) Parse(value string) (time.Time, error) {
+	return time.Parse(string(layout), value)
+}
+
+func (layout timestampParser) Format(value time.Time) string {
+	return value.Format(string(layout))
+}
+
+// TypedFlag is a flag with a value of type T, which is parsed by its Parser
+type TypedFlag[T any] struct {
+	Name        string
+	Aliases     []string
+	Usage       string
+	EnvVars     []string
+	FilePath    string
+	Required    bool
+	Hidden      bool
+	Parser      ValueParser[T]
+	Value       T
+	DefaultText string
+	Destination *T
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
+	HasBeenSet  bool
+	Placeholder string
+}
+
+// IsSet returns whether or not the flag has been set through env or file
+func (f *TypedFlag[T]) IsSet() bool {
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
+}
+
+// String returns a readable representation of this value
+// (for usage defaults)
+func (f *TypedFlag[T]) String() string {
+	return FlagStringer(f)
+}
+
+// Names returns the names of the flag
+func (f *TypedFlag[T]) Names() []string {
+	return flagNames(f.Name, f.Aliases)
+}
+
+// IsRequired returns whether or not the flag is required
+func (f *TypedFlag[T]) IsRequired() bool {
+	return f.Required
+}
+
+// TakesValue returns true of the flag takes a value, otherwise false
+func (f *TypedFlag[T]) TakesValue() bool {
+	return true
+}
+
+// GetUsage returns the usage string for the flag
+func (f *TypedFlag[T]) GetUsage() string {
+	return f.Usage
+}
+
+// GetValue returns the flags value as string representation and an empty
+// string if the flag takes no value at all.
+func (f *TypedFlag[T]) GetValue() string {
+	if f.Parser == nil {
+		return ""
+	}
+	return f.Parser.Format(f.Value)
+}
+
+// formatDefaultValue formats the default value for help with the Parser,
+// like GetValue
+func (f *TypedFlag[T]) formatDefaultValue() string {
+	return f.GetValue()
+}
+
+// IsVisible returns true if the flag is not hidden, otherwise false
+func (f *TypedFlag[T]) IsVisible() bool {
+	return !f.Hidden
+}
+
+// validateDefinition reports a TypedFlag without a Parser
+func (f *TypedFlag[T]) validateDefinition() error {
+	if f.Parser == nil {
+		return fmt.Errorf("parser must be provided for TypedFlag")
+	}
+	return nil
+}
+
+// Apply populates the flag given the flag set and environment
+func (f *TypedFlag[T]) Apply(set *flag.FlagSet) error {
+	if err := f.validateDefinition(); err != nil {
+		return err
+	}
+
+	destination := f.Destination
+	if destination == nil {
+		destination = new(T)
+	}
+	*destination = f.Value
+
+	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
+		if val != "" {
+			parsed, err := f.Parser.Parse(val)
+			if err != nil {
+				return fmt.Errorf("could not parse %q as value for flag %s: %s", val, f.Name, err)
+			}
+			*destination = parsed
+		}
+	}
+
+	names := f.Names()
+	set.Var(&typedValue[T]{parser: f.Parser, destination: destination}, names[0], f.Usage)
+	defineAliases(set, names, f.Usage)
+
+	return nil
+}
+
+// typedValue is the flag.Value of a TypedFlag
+type typedValue[T any] struct {
+	parser      ValueParser[T]
+	destination *T
+}
+
+func (v *typedValue[T]) Set(value string) error {
+	parsed, err := v.parser.Parse(value)
+	if err != nil {
+		return err
+	}
+	*v.destination = parsed
+	return nil
+}
+
+func (v *typedValue[T]) String() string {
+	// the flag package calls String on zero values
+	if v.parser == nil || v.destination == nil {
+		return ""
+	}
+	return v.parser.Format(*v.destination)
+}
+
+func (v *typedValue[T]) Get() interface{} {
+	return *v.destination
+}
+
+// TypedSliceFlag is a flag collecting values of type T, each parsed by its
+// Parser
+type TypedSliceFlag[T any] struct {
+	Name        string
+	Aliases     []string
+	Usage       string
+	EnvVars     []string
+	FilePath    string
+	Required    bool
+	Hidden      bool
+	Parser      ValueParser[T]
+	Value       []T
+	DefaultText string
+	Destination *[]T
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
+	HasBeenSet  bool
+	Placeholder string
+}
+
+// IsSet returns whether or not the flag has been set through env or file
+func (f *TypedSliceFlag[T]) IsSet() bool {
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, true)
+}
+
+// String returns a readable representation of this value
+// (for usage defaults)
+func (f *TypedSliceFlag[T]) String() string {
+	return FlagStringer(f)
+}
+
+// Names returns the names of the flag
+func (f *TypedSliceFlag[T]) Names() []string {
+	return flagNames(f.Name, f.Aliases)
+}
+
+// IsRequired returns whether or not the flag is required
+func (f *TypedSliceFlag[T]) IsRequired() bool {
+	return f.Required
+}
+
+// TakesValue returns true of the flag takes a value, otherwise false
+func (f *TypedSliceFlag[T]) TakesValue() bool {
+	return true
+}
+
+// GetUsage returns the usage string for the flag
+func (f *TypedSliceFlag[T]) GetUsage() string {
+	return f.Usage
+}
+
+// GetValue returns the flags value as string representation and an empty
+// string if the flag takes no value at all.
+func (f *TypedSliceFlag[T]) GetValue() string {
+	return strings.Join(f.defaultValues(), ", ")
+}
+
+// IsVisible returns true if the flag is not hidden, otherwise false
+func (f *TypedSliceFlag[T]) IsVisible() bool {
+	return !f.Hidden
+}
+
+// defaultValues returns the formatted default values for help
+func (f *TypedSliceFlag[T]) defaultValues() []string {
+	if f.Parser == nil {
+		return nil
+	}
+	var values []string
+	for _, v := range f.Value {
+		values = append(values, f.Parser.Format(v))
+	}
+	return values
+}
+
+// validateDefinition reports a TypedSliceFlag without a Parser
+func (f *TypedSliceFlag[T]) validateDefinition() error {
+	if f.Parser == nil {
+		return fmt.Errorf("parser must be provided for TypedSliceFlag")
+	}
+	return nil
+}
+
+// Apply populates the flag given the flag set and environment
+func (f *TypedSliceFlag[T]) Apply(set *flag.FlagSet) error {
+	if err := f.validateDefinition(); err != nil {
+		return err
+	}
+
+	destination := f.Destination
+	if destination == nil {
+		destination = new([]T)
+	}
+	*destination = append([]T(nil), f.Value...)
+	value := &typedSliceValue[T]{parser: f.Parser, destination: destination}
+
+	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
+		for _, s := range strings.Split(val, ",") {
+			if err := value.Set(strings.TrimSpace(s)); err != nil {
+				return fmt.Errorf("could not parse %q as slice value for flag %s: %s", val, f.Name, err)
+			}
+		}
+
+		// Set this to false so that we reset the slice if we then set values from
+		// flags that have already been set by the environment.
+		value.hasBeenSet = false
+	}
+
+	for _, name := range f.Names() {
+		set.Var(value, name, f.Usage)
+	}
+
+	return nil
+}
+
+// typedSliceValue is the flag.Value of a TypedSliceFlag
+type typedSliceValue[T any] struct {
+	parser      ValueParser[T]
+	destination *[]T
+	hasBeenSet  bool
+}
+
+// Set parses the value and appends it, replacing the default values first
+func (v *typedSliceValue[T]) Set(value string) error {
+	parsed, err := v.parser.Parse(value)
+	if err != nil {
+		return err
+	}
+	if !v.hasBeenSet {
+		*v.destination = nil
+		v.hasBeenSet = true
+	}
+	*v.destination = append(*v.destination, parsed)
+	return nil
+}
+
+func (v *typedSliceValue[T]) String() string {
+	// the flag package calls String on zero values
+	if v.parser == nil || v.destination == nil {
+		return ""
+	}
+	values := make([]string, 0, len(*v.destination))
+	for _, value := range *v.destination {
+		values = append(values, v.parser.Format(value))
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+func (v *typedSliceValue[T]) Get() interface{} {
+	return *v.destination
+}
+
+// Accumulates allows the value to fulfill AccumulatingValue
+func (v *typedSliceValue[T]) Accumulates() bool {
+	return true
+}
+
+// Get looks up the value of a local flag of type T, such as a TypedFlag[T],
+// or []T for a TypedSliceFlag[T]. Returns the zero value of T if not found.
+func Get[T any](c *Context, name string) T {
+	for _, ctx := range c.Lineage() {
+		if fs := ctx.lookupFlagSet(name); fs != nil {
+			if f := flagSetLookupWithValueSet(fs, name); f != nil {
+				return lookupTyped[T](f)
+			}
+		}
+	}
+	var zero T
+	return zero
+}
+
+func lookupTyped[T any](f *flag.Flag) T {
+	if getter, ok := f.Value.(flag.Getter); ok {
+		if value, ok := getter.Get().(T); ok {
+			return value
+		}
+	}
+	var zero T
+	return zero
+}
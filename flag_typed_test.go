@@ -0,0 +1,127 @@
+//go:build go1.18
+// +build go1.18
+
+package cli
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypedFlagHelpOutput(t *testing.T) {
+	tests := []struct {
+		flag     Flag
+		expected string
+	}{
+		{&TypedFlag[uint]{Name: "workers", Parser: UintParser, Value: 4, Placeholder: "N"}, "--workers N\t(default: 4)"},
+		{&TypedFlag[time.Duration]{Name: "t", Parser: DurationParser, Value: time.Minute, Placeholder: "D"}, "-t D\t(default: 1m0s)"},
+		{&TypedFlag[string]{Name: "name", Aliases: []string{"n"}, Parser: StringParser, Value: "x", Placeholder: "S"}, "--name S, -n S\t(default: \"x\")"},
+		{&TypedFlag[time.Time]{
+			Name:        "since",
+			Parser:      TimestampParser("2006-01-02"),
+			Value:       time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+			Placeholder: "DATE",
+		}, "--since DATE\t(default: 2020-01-02)"},
+		{&TypedSliceFlag[uint]{Name: "ports", Parser: UintParser, Placeholder: "N"}, "--ports N\t(accepts multiple inputs)"},
+		{&TypedSliceFlag[uint]{Name: "ports", Parser: UintParser, Value: []uint{80, 443}, Placeholder: "N"}, "--ports N\t(default: 80, 443)\t(accepts multiple inputs)"},
+		{&TypedSliceFlag[time.Time]{
+			Name:        "at",
+			Parser:      TimestampParser("2006-01-02"),
+			Value:       []time.Time{time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)},
+			Placeholder: "DATE",
+		}, "--at DATE\t(default: 2020-03-01)\t(accepts multiple inputs)"},
+	}
+
+	for _, test := range tests {
+		if output := test.flag.String(); output != test.expected {
+			t.Errorf("%q does not match %q", output, test.expected)
+		}
+	}
+}
+
+func TestParseTypedFlags(t *testing.T) {
+	var workers uint
+	var ports []uint
+	var timeout time.Duration
+	var at []time.Time
+
+	app := &App{
+		Writer: ioutil.Discard,
+		Flags: []Flag{
+			&TypedFlag[uint]{Name: "workers", Aliases: []string{"w"}, Parser: UintParser, Destination: &workers},
+			&TypedSliceFlag[uint]{Name: "port", Aliases: []string{"p"}, Parser: UintParser, Value: []uint{80}, Destination: &ports},
+			&TypedFlag[time.Duration]{Name: "timeout", Parser: DurationParser, Value: time.Second},
+			&TypedSliceFlag[time.Time]{Name: "at", Parser: TimestampParser("2006-01-02")},
+		},
+		Action: func(c *Context) error {
+			timeout = Get[time.Duration](c, "timeout")
+			at = Get[[]time.Time](c, "at")
+			expect(t, Get[uint](c, "w"), uint(8))
+			expect(t, Get[[]uint](c, "port"), []uint{8080, 8443})
+			expect(t, Get[string](c, "workers"), "")
+			expect(t, Get[int](c, "missing"), 0)
+			return nil
+		},
+	}
+
+	err := app.Run([]string{"run", "-w", "8", "--port", "8080", "-p", "8443", "--timeout", "1m", "--at", "2020-03-01"})
+	expect(t, err, nil)
+	expect(t, workers, uint(8))
+	expect(t, ports, []uint{8080, 8443})
+	expect(t, timeout, time.Minute)
+	expect(t, at, []time.Time{time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)})
+
+	err = app.Run([]string{"run", "--workers", "-1"})
+	var invalid *InvalidValueError
+	expect(t, errors.As(err, &invalid), true)
+	expect(t, invalid.Name, "workers")
+}
+
+func TestParseTypedFlagsFromEnv(t *testing.T) {
+	defer resetEnv(os.Environ())
+	os.Clearenv()
+	_ = os.Setenv("APP_WORKERS", "3")
+	_ = os.Setenv("APP_PORTS", "80, 443")
+
+	var workers uint
+	var ports []uint
+	app := &App{
+		Flags: []Flag{
+			&TypedFlag[uint]{Name: "workers", EnvVars: []string{"APP_WORKERS"}, Parser: UintParser, Destination: &workers},
+			&TypedSliceFlag[uint]{Name: "port", EnvVars: []string{"APP_PORTS"}, Parser: UintParser, Destination: &ports},
+		},
+		Action: func(*Context) error { return nil },
+	}
+
+	expect(t, app.Run([]string{"run"}), nil)
+	expect(t, workers, uint(3))
+	expect(t, ports, []uint{80, 443})
+
+	// values on the command line replace those from the environment
+	expect(t, app.Run([]string{"run", "--port", "8080"}), nil)
+	expect(t, ports, []uint{8080})
+
+	_ = os.Setenv("APP_WORKERS", "many")
+	err := app.Run([]string{"run"})
+	expect(t, err != nil, true)
+	expect(t, strings.Contains(err.Error(), `could not parse "many" as value for flag workers`), true)
+}
+
+func TestTypedFlag_MissingParser(t *testing.T) {
+	app := &App{
+		Flags: []Flag{
+			&TypedFlag[uint]{Name: "workers"},
+			&TypedSliceFlag[uint]{Name: "ports"},
+		},
+	}
+
+	err := app.Validate()
+	expect(t, err != nil, true)
+	expect(t, err.Error(), "flag --workers: parser must be provided for TypedFlag\n"+
+		"flag --ports: parser must be provided for TypedSliceFlag")
+	expect(t, app.Run([]string{"run"}).Error(), "parser must be provided for TypedFlag")
+}
@@ -18,13 +18,15 @@ type UintFlag struct {
 	Value       uint
 	DefaultText string
 	Destination *uint
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
 	HasBeenSet  bool
 	Placeholder string
 }
 
 // IsSet returns whether or not the flag has been set through env or file
 func (f *UintFlag) IsSet() bool {
-	return f.HasBeenSet
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
 }
 
 // String returns a readable representation of this value
@@ -60,6 +62,7 @@ func (f *UintFlag) IsVisible() bool {
 
 // Apply populates the flag given the flag set and environment
 func (f *UintFlag) Apply(set *flag.FlagSet) error {
+	value := f.Value
 	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
 		if val != "" {
 			valInt, err := strconv.ParseUint(val, 0, 64)
@@ -67,18 +70,17 @@ func (f *UintFlag) Apply(set *flag.FlagSet) error {
 				return fmt.Errorf("could not parse %q as uint value for flag %s: %s", val, f.Name, err)
 			}
 
-			f.Value = uint(valInt)
-			f.HasBeenSet = true
+			value = uint(valInt)
 		}
 	}
 
-	for _, name := range f.Names() {
-		if f.Destination != nil {
-			set.UintVar(f.Destination, name, f.Value, f.Usage)
-			continue
-		}
-		set.Uint(name, f.Value, f.Usage)
+	names := f.Names()
+	if f.Destination != nil {
+		set.UintVar(f.Destination, names[0], value, f.Usage)
+	} else {
+		set.Uint(names[0], value, f.Usage)
 	}
+	defineAliases(set, names, f.Usage)
 
 	return nil
 }
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseURL parses an absolute URL, such as https://example.com/path. If
+// schemes are given, the scheme of the URL must be one of them.
+func ParseURL(s string, schemes ...string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid URL", s)
+	}
+	// url.Parse reads host:port, such as localhost:8080, as a scheme and an
+	// opaque part
+	if u.Scheme == "" || u.Opaque != "" {
+		return nil, fmt.Errorf("%q is not an absolute URL with a scheme such as https://", s)
+	}
+	if len(schemes) == 0 {
+		return u, nil
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("scheme %q of URL %q is not one of %s", u.Scheme, s, strings.Join(schemes, ", "))
+}
+
+// URLFlag is a flag with type *url.URL, which must be an absolute URL.
+// If Schemes are given, the scheme of the URL must be one of them.
+type URLFlag struct {
+	Name        string
+	Aliases     []string
+	Usage       string
+	EnvVars     []string
+	FilePath    string
+	Required    bool
+	Hidden      bool
+	Value       *url.URL
+	DefaultText string
+	Destination *url.URL
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
+	HasBeenSet  bool
+	Placeholder string
+	Schemes     []string
+}
+
+// IsSet returns whether or not the flag has been set through env or file
+func (f *URLFlag) IsSet() bool {
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, false)
+}
+
+// String returns a readable representation of this value
+// (for usage defaults)
+func (f *URLFlag) String() string {
+	return FlagStringer(f)
+}
+
+// Names returns the names of the flag
+func (f *URLFlag) Names() []string {
+	return flagNames(f.Name, f.Aliases)
+}
+
+// IsRequired returns whether or not the flag is required
+func (f *URLFlag) IsRequired() bool {
+	return f.Required
+}
+
+// TakesValue returns true of the flag takes a value, otherwise false
+func (f *URLFlag) TakesValue() bool {
+	return true
+}
+
+// GetUsage returns the usage string for the flag
+func (f *URLFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetValue returns the flags value as string representation and an empty
+// string if the flag takes no value at all.
+func (f *URLFlag) GetValue() string {
+	if f.Value != nil {
+		return f.Value.String()
+	}
+	return ""
+}
+
+// IsVisible returns true if the flag is not hidden, otherwise false
+func (f *URLFlag) IsVisible() bool {
+	return !f.Hidden
+}
+
+// Apply populates the flag given the flag set and environment
+func (f *URLFlag) Apply(set *flag.FlagSet) error {
+	value := &urlValue{destination: f.Destination, schemes: f.Schemes}
+	if f.Value != nil {
+		value.setURL(f.Value)
+	} else if f.Destination != nil {
+		*f.Destination = url.URL{}
+	}
+
+	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
+		if val != "" {
+			if err := value.Set(val); err != nil {
+				return fmt.Errorf("could not parse %q as URL value for flag %s: %s", val, f.Name, err)
+			}
+		}
+	}
+
+	names := f.Names()
+	set.Var(value, names[0], f.Usage)
+	defineAliases(set, names, f.Usage)
+
+	return nil
+}
+
+// urlValue is the flag.Value of a URLFlag, which keeps the URL it was last
+// set to and copies it to the destination, if any
+type urlValue struct {
+	url         *url.URL
+	destination *url.URL
+	schemes     []string
+}
+
+func (v *urlValue) setURL(u *url.URL) {
+	copied := *u
+	v.url = &copied
+	if v.destination != nil {
+		*v.destination = copied
+	}
+}
+
+func (v *urlValue) Set(s string) error {
+	u, err := ParseURL(s, v.schemes...)
+	if err != nil {
+		return err
+	}
+	v.setURL(u)
+	return nil
+}
+
+func (v *urlValue) String() string {
+	if v.url == nil {
+		return ""
+	}
+	return v.url.String()
+}
+
+func (v *urlValue) Get() interface{} {
+	return v.url
+}
+
+// URL looks up the value of a local URLFlag, returns
+// nil if not found
+func (c *Context) URL(name string) *url.URL {
+	for _, ctx := range c.Lineage() {
+		if fs := ctx.lookupFlagSet(name); fs != nil {
+			if f := flagSetLookupWithValueSet(fs, name); f != nil {
+				return lookupURL(f)
+			}
+		}
+	}
+	return nil
+}
+
+func lookupURL(f *flag.Flag) *url.URL {
+	if getter, ok := f.Value.(flag.Getter); ok {
+		if u, ok := getter.Get().(*url.URL); ok {
+			return u
+		}
+	}
+	return nil
+}
@@ -5,27 +5,42 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"text/tabwriter"
 	"text/template"
 	"unicode/utf8"
 )
 
-var helpCommand = &Command{
-	Name:      "help",
-	Aliases:   []string{"h"},
-	Usage:     "Shows a list of commands or help for one command",
-	ArgsUsage: "[command]",
-	Action: func(c *Context) error {
-		args := c.Args()
-		if args.Present() {
-			return ShowCommandHelp(c, args.First())
-		}
+var helpCommand = newHelpCommand()
+
+// newHelpCommand returns a help command, which each App and Command with
+// subcommands gets a copy of with its own HelpName
+func newHelpCommand() *Command {
+	return &Command{
+		Name:      helpCommandName,
+		Aliases:   []string{"h"},
+		Usage:     "Shows a list of commands or help for one command",
+		ArgsUsage: "[command]",
+		Action: func(c *Context) error {
+			args := c.Args()
+			if args.Present() {
+				return ShowCommandHelp(c, args.First())
+			}
 
-		_ = ShowAppHelp(c)
-		return nil
-	},
+			// the help command of a subcommand shows the help of that subcommand
+			if scope := c.commandScope(); scope != nil && !scope.isRoot {
+				return scope.showHelp(c)
+			}
+
+			_ = ShowAppHelp(c)
+			return nil
+		},
+	}
 }
 
+// helpCommandName is the name of the help command
+const helpCommandName = "help"
+
 var helpSubcommand = &Command{
 	Name:      "help",
 	Aliases:   []string{"h"},
@@ -85,14 +100,14 @@ func ShowAppHelp(c *Context) error {
 	}
 
 	if c.App.ExtraInfo == nil {
-		HelpPrinter(c.App.Writer, tpl, c.App)
+		c.App.helpPrinter()(c.App.Writer, tpl, c.App)
 		return nil
 	}
 
 	customAppData := func() map[string]interface{} {
-		return map[string]interface{}{
-			"ExtraInfo": c.App.ExtraInfo,
-		}
+		funcs := c.App.helpFuncs()
+		funcs["ExtraInfo"] = c.App.ExtraInfo
+		return funcs
 	}
 	HelpPrinterCustom(c.App.Writer, tpl, c.App, customAppData())
 
@@ -196,25 +211,20 @@ func ShowCommandHelpAndExit(c *Context, command string, code int) {
 func ShowCommandHelp(ctx *Context, command string) error {
 	// show the subcommand help for a command with subcommands
 	if command == "" {
-		HelpPrinter(ctx.App.Writer, SubcommandHelpTemplate, ctx.App)
+		var data interface{} = ctx.App
+		if scope := ctx.commandScope(); scope != nil && !scope.isRoot {
+			data = scope
+		}
+		ctx.App.helpPrinter()(ctx.App.Writer, SubcommandHelpTemplate, data)
 		return nil
 	}
 
-	for _, c := range ctx.App.Commands {
-		if c.HasName(command) {
-			templ := c.CustomHelpTemplate
-			if templ == "" {
-				templ = CommandHelpTemplate
-			}
-
-			HelpPrinter(ctx.App.Writer, templ, c)
-
-			return nil
-		}
+	if c := ctx.lookupCommand(command); c != nil {
+		return c.showHelp(ctx)
 	}
 
 	if ctx.App.CommandNotFound == nil {
-		return Exit(fmt.Sprintf("No help topic for '%v'", command), 3)
+		return Exit(&UnknownCommandError{Name: command}, 3)
 	}
 
 	ctx.App.CommandNotFound(ctx, command)
@@ -233,8 +243,8 @@ func ShowSubcommandHelp(c *Context) error {
 		return nil
 	}
 
-	if c.Command != nil {
-		return ShowCommandHelp(c, c.Command.Name)
+	if c.Command != nil && c.Command.Name != "" && !c.Command.isRoot {
+		return c.Command.showHelp(c)
 	}
 
 	return ShowCommandHelp(c, "")
@@ -259,15 +269,9 @@ func ShowCompletions(c *Context) {
 
 // ShowCommandCompletions prints the custom completions for a given command
 func ShowCommandCompletions(ctx *Context, command string) {
-	c := ctx.App.Command(command)
-	if c != nil {
-		if c.BashComplete != nil {
-			c.BashComplete(ctx)
-		} else {
-			DefaultCompleteWithFlags(c)(ctx)
-		}
+	if c := ctx.lookupCommand(command); c != nil {
+		c.complete(ctx)
 	}
-
 }
 
 // printHelpCustom is the default implementation of HelpPrinterCustom.
@@ -275,23 +279,8 @@ func ShowCommandCompletions(ctx *Context, command string) {
 // The customFuncs map will be combined with a default template.FuncMap to
 // allow using arbitrary functions in template rendering.
 func printHelpCustom(out io.Writer, templ string, data interface{}, customFuncs map[string]interface{}) {
-
-	funcMap := template.FuncMap{
-		"join":    strings.Join,
-		"indent":  indent,
-		"nindent": nindent,
-		"trim":    strings.TrimSpace,
-		"wrap":    func(input string, offset int) string { return wrap(input, offset, HelpWrapAt) },
-		"wrapFlags":    FlagsStringer,
-		"offset":  offset,
-	}
-
-	for key, value := range customFuncs {
-		funcMap[key] = value
-	}
-
 	w := tabwriter.NewWriter(out, 1, 8, 2, ' ', 0)
-	t := template.Must(template.New("help").Funcs(funcMap).Parse(templ))
+	t := helpTemplate(templ, customFuncs)
 
 	err := t.Execute(w, data)
 	if err != nil {
@@ -305,13 +294,92 @@ func printHelpCustom(out io.Writer, templ string, data interface{}, customFuncs
 	_ = w.Flush()
 }
 
+// helpFuncMap returns the default template functions of help templates. They
+// look up the package level settings when called, so that templates parsed
+// with them can be cached.
+func helpFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"join":       strings.Join,
+		"indent":     indent,
+		"nindent":    nindent,
+		"trim":       strings.TrimSpace,
+		"wrap":       func(input string, offset int) string { return wrap(input, offset, HelpWrapAt) },
+		"wrapFlags":  func(flags []Flag, indent int) []string { return FlagsStringer(flags, indent) },
+		"flagString": func(f Flag) string { return FlagStringer(f) },
+		"offset":     offset,
+	}
+}
+
+// helpTemplates caches the help templates parsed with the default template
+// functions by their text
+var helpTemplates sync.Map
+
+// helpTemplate returns the parsed help template. Templates are parsed once,
+// unless customFuncs adds functions to the default ones.
+func helpTemplate(templ string, customFuncs map[string]interface{}) *template.Template {
+	funcMap := helpFuncMap()
+	for key := range customFuncs {
+		if _, ok := funcMap[key]; !ok {
+			for key, value := range customFuncs {
+				funcMap[key] = value
+			}
+			return template.Must(template.New("help").Funcs(funcMap).Parse(templ))
+		}
+	}
+
+	cached, ok := helpTemplates.Load(templ)
+	if !ok {
+		t := template.Must(template.New("help").Funcs(funcMap).Parse(templ))
+		cached, _ = helpTemplates.LoadOrStore(templ, t)
+	}
+	t := cached.(*template.Template)
+	if len(customFuncs) == 0 {
+		return t
+	}
+
+	// replacing functions needs a copy of the template, but no parsing, as
+	// the cached one may be executed concurrently
+	return template.Must(t.Clone()).Funcs(customFuncs)
+}
+
 func printHelp(out io.Writer, templ string, data interface{}) {
 	HelpPrinterCustom(out, templ, data, nil)
 }
 
+// helpPrinter returns the HelpPrinter of the App, falling back to the package
+// level HelpPrinter unless the App customizes the default one
+func (a *App) helpPrinter() helpPrinter {
+	if a.HelpPrinter != nil {
+		return a.HelpPrinter
+	}
+	if a.FlagStringer == nil && a.HelpWrapAt == 0 {
+		return HelpPrinter
+	}
+	return func(out io.Writer, templ string, data interface{}) {
+		HelpPrinterCustom(out, templ, data, a.helpFuncs())
+	}
+}
+
+// helpFuncs returns the template functions which depend on the settings of
+// the App
+func (a *App) helpFuncs() map[string]interface{} {
+	flagStringer, wrapAt := a.flagStringer(), a.helpWrapAt()
+	return map[string]interface{}{
+		"wrap": func(input string, offset int) string {
+			return wrap(input, offset, wrapAt)
+		},
+		"wrapFlags": func(flags []Flag, indent int) []string {
+			return flagsStrings(flags, indent, flagStringer, wrapAt)
+		},
+		"flagString": func(f Flag) string {
+			return flagStringer(f)
+		},
+	}
+}
+
 func checkVersion(c *Context) bool {
 	found := false
-	for _, name := range VersionFlag.Names() {
+	for _, name := range c.App.versionFlag().Names() {
 		if c.Bool(name) {
 			found = true
 		}
@@ -320,8 +388,13 @@ func checkVersion(c *Context) bool {
 }
 
 func checkHelp(c *Context) bool {
+	helpFlag := c.App.helpFlag()
+	if helpFlag == nil {
+		return false
+	}
+
 	found := false
-	for _, name := range HelpFlag.Names() {
+	for _, name := range helpFlag.Names() {
 		if c.Bool(name) {
 			found = true
 		}
@@ -329,24 +402,6 @@ func checkHelp(c *Context) bool {
 	return found
 }
 
-func checkCommandHelp(c *Context, name string) bool {
-	if c.Bool("h") || c.Bool("help") {
-		_ = ShowCommandHelp(c, name)
-		return true
-	}
-
-	return false
-}
-
-func checkSubcommandHelp(c *Context) bool {
-	if c.Bool("h") || c.Bool("help") {
-		_ = ShowSubcommandHelp(c)
-		return true
-	}
-
-	return false
-}
-
 func checkShellCompleteFlag(a *App, arguments []string) (bool, []string) {
 	if !a.EnableBashCompletion {
 		return false, arguments
@@ -362,32 +417,6 @@ func checkShellCompleteFlag(a *App, arguments []string) (bool, []string) {
 	return true, arguments[:pos]
 }
 
-func checkCompletions(c *Context) bool {
-	if !c.shellComplete {
-		return false
-	}
-
-	if args := c.Args(); args.Present() {
-		name := args.First()
-		if cmd := c.App.Command(name); cmd != nil {
-			// let the command handle the completion
-			return false
-		}
-	}
-
-	ShowCompletions(c)
-	return true
-}
-
-func checkCommandCompletions(c *Context, name string) bool {
-	if !c.shellComplete {
-		return false
-	}
-
-	ShowCommandCompletions(c, name)
-	return true
-}
-
 func indent(spaces int, v string) string {
 	pad := strings.Repeat(" ", spaces)
 	return pad + strings.Replace(v, "\n", "\n"+pad, -1)
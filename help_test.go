@@ -1259,3 +1259,27 @@ OPTIONS:
 			output.String(), expected)
 	}
 }
+
+func TestHelpTemplate_Cached(t *testing.T) {
+	const templ = `{{wrap "cached template" 0}}`
+
+	t1 := helpTemplate(templ, nil)
+	expect(t, helpTemplate(templ, nil), t1)
+
+	var output bytes.Buffer
+	HelpPrinterCustom(&output, templ, nil, map[string]interface{}{
+		"wrap": func(string, int) string { return "custom wrap" },
+	})
+	expect(t, output.String(), "custom wrap")
+
+	output.Reset()
+	HelpPrinterCustom(&output, `{{shout "added"}}`, nil, map[string]interface{}{
+		"shout": strings.ToUpper,
+	})
+	expect(t, output.String(), "ADDED")
+
+	// the cached template keeps the default functions
+	output.Reset()
+	HelpPrinterCustom(&output, templ, nil, nil)
+	expect(t, output.String(), "cached template")
+}
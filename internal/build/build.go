@@ -5,13 +5,19 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"go/format"
 	"io/ioutil"
 	"log"
 	"math"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"text/template"
 
 	"github.com/urfave/cli/v2"
 )
@@ -45,6 +51,10 @@ func main() {
 			Name:   "check-binary-size",
 			Action: checkBinarySizeActionFunc,
 		},
+		{
+			Name:   "generate",
+			Action: GenerateActionFunc,
+		},
 	}
 
 	err := app.Run(os.Args)
@@ -290,3 +300,466 @@ func getSize(sourcePath string, builtPath string) (size int64, err error) {
 
 	return size, nil
 }
+
+// flagSpec describes a flag type of the cli package, such as "String" for
+// cli.StringFlag, and how its altsrc wrapper applies values of input sources:
+// "value" sets a single value, "values" sets each element of a list, and
+// "custom" leaves ApplyInputSourceValue to altsrc/flag.go
+type flagSpec struct {
+	Type  string
+	Apply string
+}
+
+var flagTypePattern = regexp.MustCompile(`(?m)^type ([A-Z]\w*Flag) struct {`)
+
+var altsrcTemplate = template.Must(template.New("altsrc").Parse(`// Code generated by internal/build; DO NOT EDIT.
+
+package altsrc
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+{{range .}}
+// {{.Type}}Flag is the flag type that wraps cli.{{.Type}}Flag to allow
+// for other values to be specified
+type {{.Type}}Flag struct {
+	*cli.{{.Type}}Flag
+	set *flag.FlagSet
+}
+
+// New{{.Type}}Flag creates a new {{.Type}}Flag
+func New{{.Type}}Flag(fl *cli.{{.Type}}Flag) *{{.Type}}Flag {
+	return &{{.Type}}Flag{ {{- .Type}}Flag: fl, set: nil}
+}
+
+// Apply saves the flagSet for later usage calls, then calls
+// the wrapped {{.Type}}Flag.Apply
+func (f *{{.Type}}Flag) Apply(set *flag.FlagSet) error {
+	f.set = set
+	return f.{{.Type}}Flag.Apply(set)
+}
+{{- if eq .Apply "value"}}
+
+// ApplyInputSourceValue applies a {{.Type}} value to the flagSet if required
+func (f *{{.Type}}Flag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			value, ok, err := sourceString(isc, f.{{.Type}}Flag.Name, f.{{.Type}}Flag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which a slice valued
+			// flag would append to once per name
+			if ok {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+{{- else if eq .Apply "values"}}
+
+// ApplyInputSourceValue applies {{.Type}} values to the flagSet if required
+func (f *{{.Type}}Flag) ApplyInputSourceValue(context *cli.Context, isc InputSourceContext) error {
+	if f.set != nil {
+		if !(context.IsSet(f.Name) || isEnvVarSet(f.EnvVars)) {
+			values, err := sourceStrings(isc, f.{{.Type}}Flag.Name, f.{{.Type}}Flag)
+			if err != nil {
+				return err
+			}
+			// the names of the flag share its value, which replaces its
+			// default values when first set
+			for _, value := range values {
+				if err := f.set.Set(f.Names()[0], value); err != nil {
+					return fmt.Errorf("could not parse %q as slice value for flag %s: %s", value, f.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+{{- end}}
+{{end}}`))
+
+// sliceFlagSpec describes a slice flag type of the cli package collecting
+// values of type Elem, each parsed by the Go expression Parse of the string
+// value, which can refer to the flag as f
+type sliceFlagSpec struct {
+	Type     string
+	Elem     string
+	Kind     string
+	Doc      string
+	Parse    string
+	Fields   []sliceFlagField
+	Validate bool
+}
+
+// sliceFlagField is a field of a slice flag type in addition to the fields
+// of every flag, used by its Parse expression
+type sliceFlagField struct {
+	Name string
+	Type string
+}
+
+// sliceFlagImports are the imports of the generated slice flags by the
+// package qualifiers of their element types
+var sliceFlagImports = map[string]string{
+	"net.": "net",
+	"url.": "net/url",
+}
+
+var sliceFlagTemplate = template.Must(template.New("slice").Funcs(template.FuncMap{
+	"comment": comment,
+	"unexport": func(s string) string {
+		for i, r := range s {
+			if r >= 'a' && r <= 'z' {
+				if i > 1 {
+					i--
+				}
+				return strings.ToLower(s[:i]) + s[i:]
+			}
+		}
+		return strings.ToLower(s)
+	},
+}).Parse(`// Code generated by internal/build; DO NOT EDIT.
+
+package cli
+
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+{{range .Flags}}{{$value := printf "%sSliceValue" (unexport .Type)}}
+{{comment (printf "%sSliceFlag is a flag with %s" .Type .Doc)}}
+type {{.Type}}SliceFlag struct {
+	Name        string
+	Aliases     []string
+	Usage       string
+	EnvVars     []string
+	FilePath    string
+	Required    bool
+	Hidden      bool
+	Value       []{{.Elem}}
+	DefaultText string
+	Destination *[]{{.Elem}}
+	// Deprecated: running an App no longer sets HasBeenSet. Use
+	// Context.IsSet to learn whether the flag was given.
+	HasBeenSet  bool
+	Placeholder string
+{{- range .Fields}}
+	{{.Name}} {{.Type}}
+{{- end}}
+}
+
+// IsSet returns whether or not the flag has been set through env or file
+func (f *{{.Type}}SliceFlag) IsSet() bool {
+	return f.HasBeenSet || isSetFromEnvOrFile(f.EnvVars, f.FilePath, true)
+}
+
+// String returns a readable representation of this value
+// (for usage defaults)
+func (f *{{.Type}}SliceFlag) String() string {
+	return FlagStringer(f)
+}
+
+// Names returns the names of the flag
+func (f *{{.Type}}SliceFlag) Names() []string {
+	return flagNames(f.Name, f.Aliases)
+}
+
+// IsRequired returns whether or not the flag is required
+func (f *{{.Type}}SliceFlag) IsRequired() bool {
+	return f.Required
+}
+
+// TakesValue returns true of the flag takes a value, otherwise false
+func (f *{{.Type}}SliceFlag) TakesValue() bool {
+	return true
+}
+
+// GetUsage returns the usage string for the flag
+func (f *{{.Type}}SliceFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetValue returns the flags value as string representation and an empty
+// string if the flag takes no value at all.
+func (f *{{.Type}}SliceFlag) GetValue() string {
+	return strings.Join(f.defaultValues(), ", ")
+}
+
+// IsVisible returns true if the flag is not hidden, otherwise false
+func (f *{{.Type}}SliceFlag) IsVisible() bool {
+	return !f.Hidden
+}
+
+// defaultValues returns the formatted default values for help
+func (f *{{.Type}}SliceFlag) defaultValues() []string {
+	var values []string
+	for _, v := range f.Value {
+		values = append(values, fmt.Sprint(v))
+	}
+	return values
+}
+
+// Apply populates the flag given the flag set and environment
+func (f *{{.Type}}SliceFlag) Apply(set *flag.FlagSet) error {
+{{- if .Validate}}
+	if err := f.validateDefinition(); err != nil {
+		return err
+	}
+
+{{end}}
+	destination := f.Destination
+	if destination == nil {
+		destination = new([]{{.Elem}})
+	}
+	*destination = append([]{{.Elem}}(nil), f.Value...)
+	value := &{{$value}}{
+		parse:       func(value string) ({{.Elem}}, error) { return {{.Parse}} },
+		destination: destination,
+	}
+
+	if val, ok := flagFromEnvOrFile(f.EnvVars, f.FilePath); ok {
+		for _, s := range strings.Split(val, ",") {
+			if err := value.Set(strings.TrimSpace(s)); err != nil {
+				return fmt.Errorf("could not parse %q as {{.Kind}} values for flag %s: %s", val, f.Name, err)
+			}
+		}
+
+		// Set this to false so that we reset the slice if we then set values from
+		// flags that have already been set by the environment.
+		value.hasBeenSet = false
+	}
+
+	for _, name := range f.Names() {
+		set.Var(value, name, f.Usage)
+	}
+
+	return nil
+}
+
+// {{$value}} is the flag.Value of a {{.Type}}SliceFlag
+type {{$value}} struct {
+	parse       func(string) ({{.Elem}}, error)
+	destination *[]{{.Elem}}
+	hasBeenSet  bool
+}
+
+// Set parses the value and appends it, replacing the default values first
+func (v *{{$value}}) Set(value string) error {
+	parsed, err := v.parse(value)
+	if err != nil {
+		return err
+	}
+	if !v.hasBeenSet {
+		*v.destination = nil
+		v.hasBeenSet = true
+	}
+	*v.destination = append(*v.destination, parsed)
+	return nil
+}
+
+func (v *{{$value}}) String() string {
+	// the flag package calls String on zero values
+	if v.destination == nil {
+		return ""
+	}
+	values := make([]string, 0, len(*v.destination))
+	for _, value := range *v.destination {
+		values = append(values, fmt.Sprint(value))
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+func (v *{{$value}}) Get() interface{} {
+	return *v.destination
+}
+
+// Accumulates allows the value to fulfill AccumulatingValue
+func (v *{{$value}}) Accumulates() bool {
+	return true
+}
+
+// {{.Type}}Slice looks up the value of a local {{.Type}}SliceFlag, returns
+// nil if not found
+func (c *Context) {{.Type}}Slice(name string) []{{.Elem}} {
+	for _, ctx := range c.Lineage() {
+		if fs := ctx.lookupFlagSet(name); fs != nil {
+			if f := flagSetLookupWithValueSet(fs, name); f != nil {
+				return lookup{{.Type}}Slice(f)
+			}
+		}
+	}
+	return nil
+}
+
+func lookup{{.Type}}Slice(f *flag.Flag) []{{.Elem}} {
+	if getter, ok := f.Value.(flag.Getter); ok {
+		if values, ok := getter.Get().([]{{.Elem}}); ok {
+			return values
+		}
+	}
+	return nil
+}
+{{end}}`))
+
+// comment formats text as a Go comment wrapped at 80 columns
+func comment(text string) string {
+	var lines []string
+	line := "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 80 && line != "//" {
+			lines = append(lines, line)
+			line = "//"
+		}
+		line += " " + word
+	}
+	return strings.Join(append(lines, line), "\n")
+}
+
+// generateSliceFlags generates the slice flags of the cli package listed in
+// slice-flag-spec.json
+func generateSliceFlags(root string) error {
+	b, err := ioutil.ReadFile(filepath.Join(root, "slice-flag-spec.json"))
+	if err != nil {
+		return err
+	}
+	var spec struct {
+		Flags   []sliceFlagSpec
+		Imports []string
+	}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return fmt.Errorf("invalid slice flag spec: %v", err)
+	}
+
+	imports := map[string]bool{"flag": true, "fmt": true, "strings": true}
+	for _, f := range spec.Flags {
+		for qualifier, path := range sliceFlagImports {
+			if strings.Contains(f.Elem, qualifier) {
+				imports[path] = true
+			}
+		}
+	}
+	for path := range imports {
+		spec.Imports = append(spec.Imports, path)
+	}
+	sort.Strings(spec.Imports)
+
+	var buf bytes.Buffer
+	if err := sliceFlagTemplate.Execute(&buf, spec); err != nil {
+		return err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated code: %v", err)
+	}
+	return ioutil.WriteFile(filepath.Join(root, "flag_slice_generated.go"), code, 0644)
+}
+
+// GenerateActionFunc generates the slice flags listed in slice-flag-spec.json
+// and the altsrc wrappers of the flag types listed in altsrc/flag-spec.json,
+// which must list every flag type of the cli package
+func GenerateActionFunc(_ *cli.Context) error {
+	root, err := moduleRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := generateSliceFlags(root); err != nil {
+		return err
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(root, "altsrc", "flag-spec.json"))
+	if err != nil {
+		return err
+	}
+	var spec struct {
+		Flags []flagSpec
+	}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return fmt.Errorf("invalid flag spec: %v", err)
+	}
+
+	types, err := flagTypes(root)
+	if err != nil {
+		return err
+	}
+	for _, f := range spec.Flags {
+		if !types[f.Type+"Flag"] {
+			return fmt.Errorf("flag spec lists %sFlag, which the cli package does not have", f.Type)
+		}
+		switch f.Apply {
+		case "value", "values", "custom":
+		default:
+			return fmt.Errorf("invalid apply %q of %sFlag in the flag spec", f.Apply, f.Type)
+		}
+		delete(types, f.Type+"Flag")
+	}
+	if len(types) > 0 {
+		var missing []string
+		for typ := range types {
+			missing = append(missing, typ)
+		}
+		sort.Strings(missing)
+		return fmt.Errorf("flag spec misses %s", strings.Join(missing, ", "))
+	}
+
+	var buf bytes.Buffer
+	if err := altsrcTemplate.Execute(&buf, spec.Flags); err != nil {
+		return err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated code: %v", err)
+	}
+	return ioutil.WriteFile(filepath.Join(root, "altsrc", "flag_generated.go"), code, 0644)
+}
+
+// flagTypes returns the names of the non-generic flag structs of the cli
+// package
+func flagTypes(root string) (map[string]bool, error) {
+	files, err := filepath.Glob(filepath.Join(root, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]bool{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range flagTypePattern.FindAllSubmatch(b, -1) {
+			types[string(match[1])] = true
+		}
+	}
+	return types, nil
+}
+
+// moduleRoot returns the directory of go.mod, so that go generate can run the
+// build from the altsrc directory
+func moduleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("go.mod not found")
+		}
+		dir = parent
+	}
+}
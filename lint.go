@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LintRule names a check made by App.Lint
+type LintRule string
+
+const (
+	// LintMissingUsage reports visible commands and flags without Usage
+	LintMissingUsage LintRule = "missing-usage"
+	// LintUsageStyle reports Usage which does not start with a lowercase
+	// letter or ends with a period
+	LintUsageStyle LintRule = "usage-style"
+	// LintFlagNaming reports flag names using snake_case while most use
+	// kebab-case, or the other way around
+	LintFlagNaming LintRule = "flag-naming"
+	// LintEnvPrefix reports environment variables which do not share the
+	// prefix most of the others have
+	LintEnvPrefix LintRule = "env-prefix"
+	// LintRequiredDefault reports required flags with a default value, which
+	// is never used
+	LintRequiredDefault LintRule = "required-default"
+	// LintHiddenDescription reports hidden commands without a Description
+	// explaining why they exist
+	LintHiddenDescription LintRule = "hidden-description"
+)
+
+// LintFinding is a style issue reported by App.Lint
+type LintFinding struct {
+	// Rule which reported the issue
+	Rule LintRule
+	// Full name of the command, empty for the App itself
+	Command string
+	// Name of the flag as used on the command line, empty if the issue is
+	// about the command
+	Flag string
+	// Description of the issue
+	Message string
+}
+
+// String formats the finding for a report
+func (f LintFinding) String() string {
+	var s string
+	if f.Command != "" {
+		s += fmt.Sprintf("command %q: ", f.Command)
+	}
+	if f.Flag != "" {
+		s += fmt.Sprintf("flag %s: ", f.Flag)
+	}
+	return fmt.Sprintf("%s%s [%s]", s, f.Message, f.Rule)
+}
+
+// Lint checks the definitions of the App and its commands for issues of style,
+// which make the command line interface inconsistent without breaking it. The
+// help and version flags and help commands added by Setup are not checked.
+// Each finding names the rule which reported it, so that rules can be skipped.
+func (a *App) Lint() []LintFinding {
+	l := &linter{app: a, envPrefixes: map[string]int{}}
+
+	l.flags("", a.Flags)
+	_ = a.Walk(func(path []string, c *Command) error {
+		if isHelpCommand(c) {
+			return nil
+		}
+		name := strings.Join(path, " ")
+		l.command(name, c)
+		l.flags(name, c.Flags)
+		return nil
+	})
+
+	l.flagNaming()
+	l.envPrefix()
+	return l.findings
+}
+
+// linter collects the findings of App.Lint
+type linter struct {
+	app      *App
+	findings []LintFinding
+
+	// flag names and environment variables, checked for consistency once
+	// all have been seen
+	names       []lintedName
+	envVars     []lintedName
+	envPrefixes map[string]int
+}
+
+type lintedName struct {
+	command, flag, name string
+}
+
+func (l *linter) report(rule LintRule, command, flag, format string, a ...interface{}) {
+	l.findings = append(l.findings, LintFinding{
+		Rule:    rule,
+		Command: command,
+		Flag:    flag,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (l *linter) command(name string, c *Command) {
+	if c.Hidden {
+		if c.Description == "" {
+			l.report(LintHiddenDescription, name, "", "hidden command has no description")
+		}
+	} else if c.Usage == "" {
+		l.report(LintMissingUsage, name, "", "command has no usage")
+	}
+	l.usage(name, "", c.Usage)
+}
+
+func (l *linter) flags(command string, flags []Flag) {
+	for _, f := range flags {
+		if f == l.app.helpFlag() || f == l.app.versionFlag() || f == l.app.errorFormatFlag() {
+			continue
+		}
+
+		names := f.Names()
+		if len(names) == 0 {
+			continue
+		}
+		name := prefixFor(names[0]) + names[0]
+
+		if df, ok := f.(DocGenerationFlag); ok {
+			visible := true
+			if vf, ok := f.(VisibleFlag); ok {
+				visible = vf.IsVisible()
+			}
+			if visible && df.GetUsage() == "" {
+				l.report(LintMissingUsage, command, name, "flag has no usage")
+			}
+			l.usage(command, name, df.GetUsage())
+		}
+
+		if rf, ok := f.(RequiredFlag); ok && rf.IsRequired() && hasDefault(f) {
+			l.report(LintRequiredDefault, command, name, "required flag has a default value")
+		}
+
+		for _, n := range names {
+			if len(n) > 1 {
+				l.names = append(l.names, lintedName{command, name, n})
+			}
+		}
+		for _, envVar := range envVars(f) {
+			l.envVars = append(l.envVars, lintedName{command, name, envVar})
+			l.envPrefixes[envPrefix(envVar)]++
+		}
+	}
+}
+
+func (l *linter) usage(command, flag, usage string) {
+	if usage == "" {
+		return
+	}
+	if r, _ := utf8.DecodeRuneInString(usage); unicode.IsUpper(r) {
+		l.report(LintUsageStyle, command, flag, "usage %q should start with a lowercase letter", usage)
+	}
+	if strings.HasSuffix(usage, ".") {
+		l.report(LintUsageStyle, command, flag, "usage %q should not end with a period", usage)
+	}
+}
+
+// flagNaming reports the flag names deviating from the style most use
+func (l *linter) flagNaming() {
+	var kebab, snake int
+	for _, n := range l.names {
+		if strings.Contains(n.name, "-") {
+			kebab++
+		}
+		if strings.Contains(n.name, "_") {
+			snake++
+		}
+	}
+
+	style, other, deviating := "kebab-case", "snake_case", "_"
+	if snake > kebab {
+		style, other, deviating = other, style, "-"
+	}
+	for _, n := range l.names {
+		if strings.Contains(n.name, deviating) {
+			l.report(LintFlagNaming, n.command, n.flag, "name %q uses %s, while most flags use %s", n.name, other, style)
+		}
+	}
+}
+
+// envPrefix reports the environment variables without the prefix most share
+func (l *linter) envPrefix() {
+	if len(l.envPrefixes) < 2 {
+		return
+	}
+
+	// the first prefix seen wins a tie, for stable findings
+	common := ""
+	for _, v := range l.envVars {
+		if prefix := envPrefix(v.name); l.envPrefixes[prefix] > l.envPrefixes[common] {
+			common = prefix
+		}
+	}
+	// most variables have no prefix, so there is none to expect
+	if common == "" {
+		return
+	}
+
+	for _, v := range l.envVars {
+		if envPrefix(v.name) != common {
+			l.report(LintEnvPrefix, v.command, v.flag, "environment variable %q does not start with %q like the others", v.name, common)
+		}
+	}
+}
+
+// envVars returns the EnvVars of a flag, if it has any
+func envVars(f Flag) []string {
+	fv := flagValue(f)
+	if fv.Kind() != reflect.Struct {
+		return nil
+	}
+	field := flagField(fv, "EnvVars")
+	if !field.IsValid() {
+		return nil
+	}
+	envVars, _ := field.Interface().([]string)
+	return envVars
+}
+
+// envPrefix returns the part of an environment variable up to and including
+// the first underscore
+func envPrefix(envVar string) string {
+	if i := strings.Index(envVar, "_"); i >= 0 {
+		return envVar[:i+1]
+	}
+	return ""
+}
+
+// hasDefault reports whether the Value of a flag is set in its definition
+func hasDefault(f Flag) bool {
+	fv := flagValue(f)
+	if fv.Kind() != reflect.Struct {
+		return false
+	}
+	value := flagField(fv, "Value")
+	if !value.IsValid() || reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface()) {
+		return false
+	}
+	// slice values without elements are no default either
+	if v := reflect.Indirect(value); v.Kind() == reflect.Struct {
+		if s := flagField(v, "slice"); s.Kind() == reflect.Slice {
+			return s.Len() > 0
+		}
+	}
+	return true
+}
+
+// isHelpCommand reports whether c is a copy of the help command added by Setup
+func isHelpCommand(c *Command) bool {
+	return c.Name == helpCommand.Name && c.Usage == helpCommand.Usage
+}
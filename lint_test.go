@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestApp_Lint(t *testing.T) {
+	app := &App{
+		Version: "1.0.0",
+		Flags: []Flag{
+			&StringFlag{Name: "config-file", Usage: "load the `FILE`", EnvVars: []string{"APP_CONFIG"}},
+			&BoolFlag{Name: "dry-run", Usage: "Only print what would happen.", EnvVars: []string{"APP_DRY_RUN"}},
+			&IntFlag{Name: "max_jobs", Usage: "run up to this many jobs"},
+			&StringFlag{Name: "token", Required: true, Value: "secret", EnvVars: []string{"TOKEN"}},
+			&StringFlag{Name: "trace", Hidden: true},
+		},
+		Commands: []*Command{
+			{
+				Name:  "list",
+				Usage: "list the items",
+				Flags: []Flag{
+					&BoolFlag{Name: "all", Usage: "include hidden items", Required: true},
+					&StringSliceFlag{Name: "tag", Usage: "filter by tag", Required: true, Value: NewStringSlice()},
+				},
+				Subcommands: []*Command{
+					{Name: "recent"},
+				},
+			},
+			{Name: "debug", Hidden: true},
+			{Name: "internal", Hidden: true, Description: "used by the installer"},
+		},
+	}
+
+	var findings []string
+	for _, f := range app.Lint() {
+		findings = append(findings, f.String())
+	}
+
+	expect(t, findings, []string{
+		`flag --dry-run: usage "Only print what would happen." should start with a lowercase letter [usage-style]`,
+		`flag --dry-run: usage "Only print what would happen." should not end with a period [usage-style]`,
+		`flag --token: flag has no usage [missing-usage]`,
+		`flag --token: required flag has a default value [required-default]`,
+		`command "list recent": command has no usage [missing-usage]`,
+		`command "debug": hidden command has no description [hidden-description]`,
+		`flag --max_jobs: name "max_jobs" uses snake_case, while most flags use kebab-case [flag-naming]`,
+		`flag --token: environment variable "TOKEN" does not start with "APP_" like the others [env-prefix]`,
+	})
+}
+
+func TestApp_Lint_Clean(t *testing.T) {
+	app := &App{
+		ErrorFormat: ErrorFormatText,
+		Flags: []Flag{
+			&StringFlag{Name: "config_file", Usage: "load the config", EnvVars: []string{"APP_CONFIG"}},
+			&BoolFlag{Name: "dry_run", Usage: "only print what would happen", EnvVars: []string{"APP_DRY_RUN"}},
+		},
+		Commands: []*Command{
+			{Name: "list", Usage: "list the items"},
+		},
+	}
+
+	expect(t, len(app.Lint()), 0)
+}
+
+func TestApp_Lint_EnvVarsWithoutPrefix(t *testing.T) {
+	app := &App{
+		Flags: []Flag{
+			&StringFlag{Name: "host", Usage: "connect to the host", EnvVars: []string{"HOST"}},
+			&IntFlag{Name: "port", Usage: "connect to the port", EnvVars: []string{"PORT"}},
+			&StringFlag{Name: "user", Usage: "log in as the user", EnvVars: []string{"APP_USER"}},
+		},
+	}
+
+	expect(t, len(app.Lint()), 0)
+}
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"strings"
 )
 
@@ -17,8 +19,13 @@ type iterativeParser interface {
 // Pass `shellComplete` to continue parsing options on failure during shell
 // completion when, the user-supplied options may be incomplete.
 func parseIter(set *flag.FlagSet, ip iterativeParser, args []string, shellComplete bool) error {
+	rawArgs := append([]string{}, args...)
+	return newParseError(parseIterRaw(set, ip, args, shellComplete), rawArgs)
+}
+
+func parseIterRaw(set *flag.FlagSet, ip iterativeParser, args []string, shellComplete bool) error {
 	for {
-		err := set.Parse(args)
+		err := parseSet(set, args)
 		if !ip.useShortOptionHandling() || err == nil {
 			if shellComplete {
 				return nil
@@ -46,8 +53,9 @@ func parseIter(set *flag.FlagSet, ip iterativeParser, args []string, shellComple
 				return err
 			}
 
-			// swap current argument with the split version
-			args = append(args[:i], append(shortOpts, args[i+1:]...)...)
+			// swap current argument with the split version, without writing
+			// into the backing array of the caller
+			args = append(append(args[:i:i], shortOpts...), args[i+1:]...)
 			argsWereSplit = true
 			break
 		}
@@ -67,6 +75,67 @@ func parseIter(set *flag.FlagSet, ip iterativeParser, args []string, shellComple
 	}
 }
 
+// setValueError is an error of the flag package caused by the Set method of
+// the flag.Value of a flag, which the flag package only keeps as text
+type setValueError struct {
+	error
+	name  string
+	value string
+	err   error
+}
+
+// setErrorValue wraps the flag.Value of a flag while parsing, to keep the
+// error returned by its Set method
+type setErrorValue struct {
+	flag.Value
+	name   string
+	failed **setValueError
+}
+
+func (v *setErrorValue) Set(value string) error {
+	err := v.Value.Set(value)
+	if err != nil {
+		*v.failed = &setValueError{name: v.name, value: value, err: err}
+	}
+	return err
+}
+
+func (v *setErrorValue) String() string {
+	// the flag package calls String on zero values
+	if v.Value == nil {
+		return ""
+	}
+	return v.Value.String()
+}
+
+func (v *setErrorValue) IsBoolFlag() bool {
+	bf, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
+
+// parseSet parses args with set, returning a setValueError if the Set method
+// of a flag failed
+func parseSet(set *flag.FlagSet, args []string) error {
+	var failed *setValueError
+	values := make(map[*flag.Flag]flag.Value)
+	set.VisitAll(func(f *flag.Flag) {
+		values[f] = f.Value
+		f.Value = &setErrorValue{Value: f.Value, name: f.Name, failed: &failed}
+	})
+	defer func() {
+		for f, value := range values {
+			f.Value = value
+		}
+	}()
+
+	err := set.Parse(args)
+	if err != nil && failed != nil {
+		failed.error = err
+		return failed
+	}
+	return err
+}
+
 func splitShortOptions(set *flag.FlagSet, arg string) []string {
 	shortFlagsExist := func(s string) bool {
 		for _, c := range s[1:] {
@@ -92,3 +161,67 @@ func splitShortOptions(set *flag.FlagSet, arg string) []string {
 func isSplittable(flagArg string) bool {
 	return strings.HasPrefix(flagArg, "-") && !strings.HasPrefix(flagArg, "--") && len(flagArg) > 2
 }
+
+// newParseError converts the errors of the flag package into the typed
+// usage errors of this package. Unknown errors are returned as is.
+func newParseError(err error, args []string) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+	if failed, ok := err.(*setValueError); ok {
+		return &InvalidValueError{
+			Name:    failed.name,
+			Value:   failed.value,
+			Err:     failed.err,
+			message: msg,
+		}
+	}
+	if name := strings.TrimPrefix(msg, "flag provided but not defined: -"); name != msg {
+		return &UnknownFlagError{Name: name, Arg: findFlagArg(args, name)}
+	}
+	if name := strings.TrimPrefix(msg, "flag needs an argument: -"); name != msg {
+		return &MissingValueError{Name: name, Arg: findFlagArg(args, name)}
+	}
+
+	// errors of flag sets parsed without parseSet are only known by their
+	// text
+	var value, name, prefix string
+	if n, _ := fmt.Sscanf(msg, "invalid value %q for flag -%s", &value, &name); n == 2 {
+		name = strings.TrimSuffix(name, ":")
+		prefix = fmt.Sprintf("invalid value %q for flag -%s: ", value, name)
+	} else if n, _ := fmt.Sscanf(msg, "invalid boolean value %q for -%s", &value, &name); n == 2 {
+		name = strings.TrimSuffix(name, ":")
+		prefix = fmt.Sprintf("invalid boolean value %q for -%s: ", value, name)
+	} else if n, _ := fmt.Sscanf(msg, "invalid boolean flag %s", &name); n == 1 {
+		name = strings.TrimSuffix(name, ":")
+		prefix = fmt.Sprintf("invalid boolean flag %s: ", name)
+	} else {
+		return err
+	}
+
+	return &InvalidValueError{
+		Name:    name,
+		Value:   value,
+		Err:     errors.New(strings.TrimPrefix(msg, prefix)),
+		message: msg,
+	}
+}
+
+// findFlagArg returns the argument in which the flag with the given name has
+// been used
+func findFlagArg(args []string, name string) string {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if n := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]; n == name {
+			return arg
+		}
+	}
+	return ""
+}
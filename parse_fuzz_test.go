@@ -0,0 +1,248 @@
+//go:build go1.18
+// +build go1.18
+
+package cli
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// fuzzFlags returns a representative set of flags with short and long names,
+// covering booleans, scalars and slices
+func fuzzFlags() []Flag {
+	return []Flag{
+		&BoolFlag{Name: "verbose", Aliases: []string{"v"}},
+		&BoolFlag{Name: "x"},
+		&StringFlag{Name: "name", Aliases: []string{"n"}},
+		&IntFlag{Name: "count", Aliases: []string{"c"}},
+		&DurationFlag{Name: "timeout", Aliases: []string{"d"}},
+		&StringSliceFlag{Name: "tag", Aliases: []string{"t"}},
+		&IntSliceFlag{Name: "port", Aliases: []string{"p"}},
+		&Float64SliceFlag{Name: "ratio", Aliases: []string{"r"}},
+	}
+}
+
+// fuzzArgs splits the fuzz input into arguments. Spaces separate arguments,
+// so that empty arguments can occur as well.
+func fuzzArgs(input string) []string {
+	if input == "" {
+		return nil
+	}
+	return strings.Split(input, " ")
+}
+
+// parseFuzzArgs parses args with short option handling into a new flag set,
+// failing the test if parsing does not terminate
+func parseFuzzArgs(t *testing.T, args []string, shellComplete bool) (*flag.FlagSet, error) {
+	cmd := &Command{Name: "fuzz", Flags: fuzzFlags(), UseShortOptionHandling: true}
+	set, err := cmd.newFlagSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- parseIter(set, cmd, args, shellComplete)
+	}()
+
+	select {
+	case err := <-done:
+		return set, err
+	case <-time.After(5 * time.Second):
+		t.Fatalf("parsing %q did not terminate", args)
+		return nil, nil
+	}
+}
+
+var fuzzSeeds = []string{
+	"",
+	"-v",
+	"-vx",
+	"-xv -n jerry",
+	"-vn jerry",
+	"-vc 3 arg",
+	"--name=jerry -n tom",
+	"-t a -t b --tag c",
+	"-p 1 -p 2 -r 0.5",
+	"-vz",
+	"-n",
+	"-c many",
+	"-v=false -x=true",
+	"-- -v",
+	"arg -v",
+	"-d 1m30s -vxvx",
+	"- -- ---",
+	"-r NaN",
+}
+
+func FuzzParseIter(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		args := fuzzArgs(input)
+
+		// give args spare capacity, so that appending in place would be
+		// noticed
+		backing := make([]string, len(args), len(args)+16)
+		copy(backing, args)
+
+		set, err := parseFuzzArgs(t, backing, false)
+		if strings.Join(backing, " ") != input {
+			t.Fatalf("parsing modified the arguments: %q became %q", args, backing)
+		}
+		if err != nil {
+			var (
+				unknownFlag  *UnknownFlagError
+				missingValue *MissingValueError
+				invalidValue *InvalidValueError
+			)
+			// errors of the flag package about the syntax of an argument
+			// and about help are passed on as they are
+			if err == flag.ErrHelp || strings.HasPrefix(err.Error(), "bad flag syntax: ") {
+				return
+			}
+			if !errors.As(err, &unknownFlag) && !errors.As(err, &missingValue) && !errors.As(err, &invalidValue) {
+				t.Fatalf("parsing %q returned an untyped error: %v", args, err)
+			}
+			return
+		}
+
+		for _, arg := range set.Args() {
+			if !contains(args, arg) {
+				t.Fatalf("parsing %q returned an unknown argument %q", args, arg)
+			}
+		}
+
+		if _, err := parseFuzzArgs(t, args, true); err != nil {
+			t.Fatalf("parsing %q for shell completion returned an error: %v", args, err)
+		}
+	})
+}
+
+func FuzzSplitShortOptions(f *testing.F) {
+	for _, seed := range []string{"-v", "-vx", "-vxn", "-vz", "--vx", "vx", "-", "-é", "-vé"} {
+		f.Add(seed)
+	}
+
+	set, err := flagSet("fuzz", fuzzFlags())
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	f.Fuzz(func(t *testing.T, arg string) {
+		split := splitShortOptions(set, arg)
+		if len(split) == 1 && split[0] == arg {
+			return
+		}
+
+		if !isSplittable(arg) {
+			t.Fatalf("%q was split although it is not splittable", arg)
+		}
+		joined := "-"
+		for _, opt := range split {
+			if len(opt) < 2 || opt[0] != '-' || set.Lookup(opt[1:]) == nil {
+				t.Fatalf("%q was split into %q, which contains the unknown flag %q", arg, split, opt)
+			}
+			joined += opt[1:]
+		}
+		if joined != arg {
+			t.Fatalf("%q was split into %q", arg, split)
+		}
+	})
+}
+
+func FuzzNormalizeFlags(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		args := fuzzArgs(input)
+		set, err := parseFuzzArgs(t, args, false)
+		if err != nil {
+			return
+		}
+
+		before := map[string]string{}
+		set.Visit(func(f *flag.Flag) {
+			before[f.Name] = f.Value.String()
+		})
+
+		flags := fuzzFlags()
+		if err := normalizeFlags(flags, set, AliasError); err != nil {
+			var conflict *FlagConflictError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("normalizing %q returned an untyped error: %v", args, err)
+			}
+			if !sameFlag(flags, conflict.Name, conflict.Other) {
+				t.Fatalf("normalizing %q reported a conflict between different flags: %v", args, err)
+			}
+			return
+		}
+
+		for _, fl := range flags {
+			names := fl.Names()
+			first := set.Lookup(names[0]).Value.String()
+			for _, name := range names[1:] {
+				if value := set.Lookup(name).Value.String(); value != first {
+					t.Fatalf("aliases disagree after parsing %q: %s=%s but %s=%s", args, names[0], first, name, value)
+				}
+			}
+			for _, name := range names {
+				if value, ok := before[name]; ok && value != first {
+					t.Fatalf("normalizing %q changed %s from %s to %s", args, name, value, first)
+				}
+			}
+		}
+	})
+}
+
+func FuzzSerializer(f *testing.F) {
+	f.Add("a", "b,c")
+	f.Add("", "")
+	f.Add(`"quoted"`, "sl:::0:::[]")
+
+	f.Fuzz(func(t *testing.T, a, b string) {
+		// the values are serialized as JSON, which replaces invalid UTF-8
+		if !utf8.ValidString(a) || !utf8.ValidString(b) {
+			t.Skip("invalid UTF-8 is not preserved by Serialize")
+		}
+		source := NewStringSlice(a, b)
+
+		copied := &StringSlice{}
+		if err := copied.Set(source.Serialize()); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(copied.Value(), source.Value()) {
+			t.Fatalf("copying %q produced %q", source.Value(), copied.Value())
+		}
+	})
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sameFlag reports whether a and b are names of the same flag
+func sameFlag(flags []Flag, a, b string) bool {
+	for _, fl := range flags {
+		names := fl.Names()
+		if contains(names, a) && contains(names, b) {
+			return true
+		}
+	}
+	return false
+}
@@ -26,7 +26,10 @@ COMMANDS:{{range .VisibleCategories}}{{if .Name}}
 
 GLOBAL OPTIONS:
    {{range $index, $option := .VisibleFlags}}{{if $index}}
-   {{end}}{{wrap $option.String 6}}{{end}}{{end}}{{if .Copyright}}
+   {{end}}{{wrap (flagString $option) 6}}{{end}}{{end}}{{if .VisibleExitCodes}}
+
+EXIT STATUS:{{range .VisibleExitCodes}}
+   {{.Code}}{{"\t"}}{{.Description}}{{end}}{{end}}{{if .Copyright}}
 
 COPYRIGHT:
    {{wrap .Copyright 3}}{{end}}
@@ -105,7 +108,11 @@ var MarkdownDocTemplate = `{{if gt .SectionNum 0}}% {{ .App.Name }} {{ .SectionN
 {{ end }}{{ if .Commands }}
 # COMMANDS
 {{ range $v := .Commands }}
-{{ $v }}{{ end }}{{ end }}`
+{{ $v }}{{ end }}{{ end }}{{ if .App.VisibleExitCodes }}
+# EXIT STATUS
+{{ range $v := .App.VisibleExitCodes }}
+**{{ $v.Code }}**: {{ $v.Description }}
+{{ end }}{{ end }}`
 
 var FishCompletionTemplate = `# {{ .App.Name }} fish shell completion
 
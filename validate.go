@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// definitionValidator is implemented by flags which can check their own
+// definition, e.g. for missing fields
+type definitionValidator interface {
+	validateDefinition() error
+}
+
+// Validate checks the definitions of the App and its commands for mistakes
+// which would otherwise only be noticed once the affected command is run, such
+// as several flags or commands using the same name. The help and version flags
+// and the help commands added by Setup are checked along with the others. All
+// mistakes are reported at once as a MultiError of DefinitionErrors.
+func (a *App) Validate() error {
+	a.Setup()
+	return a.validate(true)
+}
+
+// validate checks the App and its commands. Commands which have not been
+// loaded yet are loaded if load is set, and otherwise left to be checked by
+// validateLoaded once they are.
+func (a *App) validate(load bool) error {
+	if load {
+		// loading the commands before walking them keeps validateLoaded from
+		// checking them a second time
+		if err := loadCommands(a.Commands); err != nil {
+			return err
+		}
+	}
+
+	errs := validateFlags("", a.Flags)
+	errs = append(errs, validateCommands("", a.Commands)...)
+	err := a.walkCommands(nil, a.Commands, func(path []string, c *Command) error {
+		errs = append(errs, validateCommand(path, c)...)
+		return nil
+	}, false)
+	if err != nil {
+		return err
+	}
+	return definitionErrors(errs)
+}
+
+// validateLoaded checks a command which has just been loaded and the loaded
+// commands beneath it, so that a strict App does not load its lazy commands
+// to validate them
+func (a *App) validateLoaded(c *Command) error {
+	var path []string
+	for p := c; p != nil && !p.isRoot; p = p.parent {
+		path = append([]string{p.Name}, path...)
+	}
+
+	errs := validateCommand(path, c)
+	err := a.walkCommands(path, c.Subcommands, func(path []string, c *Command) error {
+		errs = append(errs, validateCommand(path, c)...)
+		return nil
+	}, false)
+	if err != nil {
+		return err
+	}
+	return definitionErrors(errs)
+}
+
+// definitionErrors returns the errors as a MultiError, or nil if there are
+// none
+func definitionErrors(errs []error) error {
+	if len(errs) > 0 {
+		return newMultiError(errs...)
+	}
+	return nil
+}
+
+// validateCommand checks the flags and subcommands of the command with the
+// given path
+func validateCommand(path []string, c *Command) []error {
+	name := strings.Join(path, " ")
+	errs := validateFlags(name, c.Flags)
+	errs = append(errs, validateCommands(name, c.Subcommands)...)
+	return append(errs, validateSubcommandNames(name, c)...)
+}
+
+// validateFlags checks the flags of the named command
+func validateFlags(command string, flags []Flag) []error {
+	var errs []error
+	fail := func(format string, a ...interface{}) {
+		errs = append(errs, &DefinitionError{Command: command, Message: fmt.Sprintf(format, a...)})
+	}
+
+	defined := map[string]Flag{}
+	for _, f := range flags {
+		names := f.Names()
+		if len(names) == 0 || names[0] == "" {
+			fail("flag without a name")
+			continue
+		}
+
+		for _, name := range names {
+			other, ok := defined[name]
+			switch {
+			case name == "":
+				fail("flag %s has an empty alias", flagName(f))
+			case ok && other == f:
+				fail("flag %s has the name %q more than once", flagName(f), name)
+			case ok:
+				fail("flag name %q is used by %s and %s", name, flagName(other), flagName(f))
+			default:
+				defined[name] = f
+			}
+		}
+
+		if v, ok := f.(definitionValidator); ok {
+			if err := v.validateDefinition(); err != nil {
+				fail("flag %s: %v", flagName(f), err)
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateCommands checks the subcommands of the named command
+func validateCommands(command string, commands []*Command) []error {
+	var errs []error
+	fail := func(format string, a ...interface{}) {
+		errs = append(errs, &DefinitionError{Command: command, Message: fmt.Sprintf(format, a...)})
+	}
+
+	defined := map[string]*Command{}
+	for _, c := range commands {
+		if c.Name == "" {
+			fail("command without a name")
+			continue
+		}
+
+		for _, name := range c.Names() {
+			other, ok := defined[name]
+			switch {
+			case name == "":
+				fail("command %q has an empty alias", c.Name)
+			case ok && other == c:
+				fail("command %q has the name %q more than once", c.Name, name)
+			case ok:
+				fail("command name %q is used by %q and %q", name, other.Name, c.Name)
+			default:
+				defined[name] = c
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateSubcommandNames checks that the subcommands of the named command
+// do not use one of its names, which makes command lines such as "app d d"
+// ambiguous to read
+func validateSubcommandNames(command string, c *Command) []error {
+	var errs []error
+	names := c.Names()
+	for _, sub := range c.Subcommands {
+		for _, name := range sub.Names() {
+			for _, parentName := range names {
+				if name != "" && name == parentName {
+					errs = append(errs, &DefinitionError{
+						Command: command,
+						Message: fmt.Sprintf("command name %q is used by %q and its subcommand %q", name, c.Name, sub.Name),
+					})
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// flagName returns the first name of f as used on the command line
+func flagName(f Flag) string {
+	name := f.Names()[0]
+	return prefixFor(name) + name
+}
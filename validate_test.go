@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+func TestApp_Validate(t *testing.T) {
+	app := newDepthApp(ioutil.Discard)
+	app.Version = "1.0.0"
+	app.Commands[0].Aliases = []string{"c"}
+	expect(t, app.Validate(), nil)
+}
+
+func TestApp_Validate_Mistakes(t *testing.T) {
+	app := &App{
+		Version: "1.0.0",
+		Flags: []Flag{
+			&StringFlag{Name: "name", Aliases: []string{"n"}},
+			&BoolFlag{Name: "nope", Aliases: []string{"n"}},
+			&BoolFlag{Name: "verbose", Aliases: []string{"V"}},
+			&ChoiceFlag{Name: "mode"},
+		},
+		Commands: []*Command{
+			{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Flags: []Flag{
+					&StringFlag{Name: "host", Aliases: []string{"h"}},
+					&IntFlag{Name: "count", Aliases: []string{"count"}},
+				},
+				Subcommands: []*Command{
+					{Name: "all"},
+					{Name: "any", Aliases: []string{"all"}},
+					{},
+				},
+			},
+			{Name: "ls"},
+		},
+	}
+
+	err := app.Validate()
+	if err == nil {
+		t.Fatal("expected definition errors")
+	}
+
+	var multi MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("expected a MultiError, got %T", err)
+	}
+
+	var messages []string
+	for _, err := range multi.Errors() {
+		var definitionErr *DefinitionError
+		if !errors.As(err, &definitionErr) {
+			t.Fatalf("expected a DefinitionError, got %T", err)
+		}
+		messages = append(messages, err.Error())
+	}
+
+	expect(t, messages, []string{
+		`flag name "n" is used by --name and --nope`,
+		`flag --mode: choice must be provided for ChoiceFlag`,
+		`flag name "V" is used by --verbose and --version`,
+		`command name "ls" is used by "list" and "ls"`,
+		`command "list": flag --count has the name "count" more than once`,
+		`command "list": flag name "h" is used by --host and --help`,
+		`command "list": command name "all" is used by "all" and "any"`,
+		`command "list": command without a name`,
+	})
+}
+
+func TestApp_Strict(t *testing.T) {
+	ran := false
+	app := &App{
+		Strict: true,
+		Writer: ioutil.Discard,
+		Flags: []Flag{
+			&StringFlag{Name: "host", Aliases: []string{"h"}},
+		},
+		Action: func(*Context) error {
+			ran = true
+			return nil
+		},
+	}
+
+	err := app.Run([]string{"app"})
+	expect(t, err != nil, true)
+	expect(t, err.Error(), `flag name "h" is used by --host and --help`)
+	expect(t, ran, false)
+
+	app.Reset()
+	app.HelpFlag = &BoolFlag{Name: "help"}
+	expect(t, app.Run([]string{"app"}), nil)
+	expect(t, ran, true)
+}
+
+func TestApp_Validate_SubcommandNames(t *testing.T) {
+	app := &App{
+		Commands: []*Command{
+			{
+				Name:    "db",
+				Aliases: []string{"d"},
+				Subcommands: []*Command{
+					{Name: "d"},
+					{Name: "dump", Aliases: []string{"db"}},
+				},
+			},
+		},
+	}
+
+	err := app.Validate()
+	if err == nil {
+		t.Fatal("expected definition errors")
+	}
+	expect(t, err.Error(), `command "db": command name "d" is used by "db" and its subcommand "d"`+"\n"+
+		`command "db": command name "db" is used by "db" and its subcommand "dump"`)
+}
+
+func TestApp_Validate_LoaderError(t *testing.T) {
+	errLoad := errors.New("cannot load")
+	app := &App{
+		Commands: []*Command{
+			{Name: "lazy", Loader: func(*Command) error { return errLoad }},
+		},
+	}
+
+	expect(t, app.Validate(), errLoad)
+}
+
+func TestApp_Strict_LazyCommand(t *testing.T) {
+	loaded := false
+	app := &App{
+		Strict: true,
+		Writer: ioutil.Discard,
+		Commands: []*Command{
+			{Name: "eager", Action: func(*Context) error { return nil }},
+			{
+				Name: "lazy",
+				Loader: func(c *Command) error {
+					loaded = true
+					c.Flags = []Flag{&StringFlag{Name: "host", Aliases: []string{"h"}}}
+					return nil
+				},
+				Action: func(*Context) error { return nil },
+			},
+		},
+	}
+
+	expect(t, app.Run([]string{"app", "eager"}), nil)
+	expect(t, loaded, false)
+
+	err := app.Run([]string{"app", "lazy"})
+	expect(t, loaded, true)
+	expect(t, err != nil, true)
+	expect(t, err.Error(), `command "lazy": flag name "h" is used by --host and --help`)
+}